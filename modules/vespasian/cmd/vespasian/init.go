@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// runInit interactively generates a starter config file. Every prompt has
+// a sensible default so hitting enter all the way through yields a working
+// configuration.
+func runInit(args []string) error {
+	path := "config.yaml"
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists; remove it first or pass another path", path)
+	}
+	return generateConfig(path, bufio.NewReader(os.Stdin), os.Stdout)
+}
+
+func generateConfig(path string, in *bufio.Reader, out io.Writer) error {
+	fmt.Fprintf(out, "Generating %s — press enter to accept defaults.\n\n", path)
+
+	ask := func(prompt, fallback string) string {
+		fmt.Fprintf(out, "%s [%s]: ", prompt, fallback)
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return fallback
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return fallback
+		}
+		return line
+	}
+
+	concurrency := ask("Concurrent probe executions", "10")
+	if _, err := strconv.Atoi(concurrency); err != nil {
+		return fmt.Errorf("invalid concurrency %q", concurrency)
+	}
+	timeout := ask("Per-request timeout", "10s")
+	format := ask("Output format (json, terminal, sarif)", "json")
+	output := ask("Output file (empty for stdout)", "")
+	artifactDir := ask("Evidence artifact directory (empty to disable)", "")
+	insecure := ask("Skip TLS certificate verification (true/false)", "false")
+	if _, err := strconv.ParseBool(insecure); err != nil {
+		return fmt.Errorf("invalid boolean %q", insecure)
+	}
+
+	var b strings.Builder
+	b.WriteString("# vespasian scan configuration\n")
+	fmt.Fprintf(&b, "concurrency: %s\n", concurrency)
+	fmt.Fprintf(&b, "timeout: %s\n", timeout)
+	fmt.Fprintf(&b, "format: %s\n", format)
+	if output != "" {
+		fmt.Fprintf(&b, "output: %s\n", output)
+	}
+	if artifactDir != "" {
+		fmt.Fprintf(&b, "artifact_dir: %s\n", artifactDir)
+	}
+	fmt.Fprintf(&b, "insecure: %s\n", insecure)
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nWrote %s. Run: vespasian scan %s <target>\n", path, path)
+	return nil
+}
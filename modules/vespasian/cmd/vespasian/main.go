@@ -0,0 +1,825 @@
+// Command vespasian discovers API endpoints across OpenAPI, GraphQL, gRPC,
+// WebSocket, and SOAP services.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/artifact"
+	"github.com/praetorian-inc/vespasian/pkg/auth/login"
+	"github.com/praetorian-inc/vespasian/pkg/auth/ntlm"
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/config"
+	"github.com/praetorian-inc/vespasian/pkg/crawler"
+	"github.com/praetorian-inc/vespasian/pkg/enrich"
+	"github.com/praetorian-inc/vespasian/pkg/har"
+	"github.com/praetorian-inc/vespasian/pkg/history"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/metrics"
+	"github.com/praetorian-inc/vespasian/pkg/notify"
+	"github.com/praetorian-inc/vespasian/pkg/objstore"
+	"github.com/praetorian-inc/vespasian/pkg/output"
+	"github.com/praetorian-inc/vespasian/pkg/policy"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/probes/crawl"
+	graphqlprobe "github.com/praetorian-inc/vespasian/pkg/probes/graphql"
+	grpcprobe "github.com/praetorian-inc/vespasian/pkg/probes/grpc"
+	"github.com/praetorian-inc/vespasian/pkg/probes/learnedpaths"
+	"github.com/praetorian-inc/vespasian/pkg/probes/plugin"
+	"github.com/praetorian-inc/vespasian/pkg/probes/soap"
+	"github.com/praetorian-inc/vespasian/pkg/recon/portscan"
+	"github.com/praetorian-inc/vespasian/pkg/recon/servicedetect"
+	"github.com/praetorian-inc/vespasian/pkg/recon/subdomains"
+	"github.com/praetorian-inc/vespasian/pkg/scan"
+	scanstore "github.com/praetorian-inc/vespasian/pkg/store"
+	"github.com/praetorian-inc/vespasian/pkg/targets"
+	"github.com/praetorian-inc/vespasian/pkg/tracing"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+	"github.com/praetorian-inc/vespasian/pkg/wordlist"
+
+	_ "github.com/praetorian-inc/vespasian/pkg/probes"
+)
+
+const version = "1.0.0"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "scan":
+		if err := runScan(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "vespasian:", err)
+			os.Exit(1)
+		}
+	case "init":
+		if err := runInit(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "vespasian:", err)
+			os.Exit(1)
+		}
+	case "history":
+		if err := runHistory(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "vespasian:", err)
+			os.Exit(1)
+		}
+	case "query":
+		if err := runQuery(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "vespasian:", err)
+			os.Exit(1)
+		}
+	case "version":
+		fmt.Println("vespasian", version)
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  vespasian scan [config.yaml] [--profile passive|safe|aggressive] [--header "Name: value"]... [-f format[=path]]... [--import scan.xml]... [--export-graphql-schema dir] <target> [<target>...]
+  vespasian init [config.yaml]
+  vespasian history <history.json> [<endpoint>]
+  vespasian query <store.db> [--scan id] [--source probe] [--diff old:new] [<url substring>]
+  vespasian version
+
+Targets are host, host:port, a full URL (https://host:9443/app),
+unix:///path/to.sock, or @file to import an inventory export.
+--import reads an nmap XML (-oX) or masscan JSON (-oJ) report and
+scans the open ports it recorded.`)
+}
+
+func runScan(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("no targets given")
+	}
+
+	// --header, --profile, and -f flags may appear anywhere among the
+	// arguments; --header, --import, and -f repeat.
+	var headerArgs []string
+	var formatArgs []string
+	var importArgs []string
+	profileArg := ""
+	schemaDirArg := ""
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--header":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--header needs a \"Name: value\" argument")
+			}
+			headerArgs = append(headerArgs, args[i+1])
+			i++
+		case "-f", "--format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--format needs a format or format=path argument")
+			}
+			formatArgs = append(formatArgs, args[i+1])
+			i++
+		case "--profile":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--profile needs a profile name")
+			}
+			profileArg = args[i+1]
+			i++
+		case "--import":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--import needs a file argument")
+			}
+			importArgs = append(importArgs, args[i+1])
+			i++
+		case "--export-graphql-schema":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--export-graphql-schema needs a directory argument")
+			}
+			schemaDirArg = args[i+1]
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	args = rest
+	if len(args) == 0 && len(importArgs) == 0 {
+		usage()
+		return fmt.Errorf("no targets given")
+	}
+
+	cfg := config.Default()
+	if strings.HasSuffix(args[0], ".yaml") || strings.HasSuffix(args[0], ".yml") {
+		loaded, err := config.Load(args[0])
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+		args = args[1:]
+	}
+	if len(args) == 0 && len(importArgs) == 0 {
+		return fmt.Errorf("no targets given")
+	}
+
+	if profileArg != "" {
+		if err := cfg.ApplyProfile(profileArg); err != nil {
+			return err
+		}
+	}
+
+	if schemaDirArg != "" {
+		cfg.GraphQLSchemaDir = schemaDirArg
+	}
+
+	for _, spec := range formatArgs {
+		format, path, _ := strings.Cut(spec, "=")
+		if _, err := output.Get(format); err != nil {
+			return err
+		}
+		cfg.Destinations = append(cfg.Destinations, config.Destination{Format: format, Path: path})
+	}
+
+	for _, header := range headerArgs {
+		name, value, ok := strings.Cut(header, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return fmt.Errorf("invalid --header %q, want \"Name: value\"", header)
+		}
+		if cfg.Headers == nil {
+			cfg.Headers = map[string]string{}
+		}
+		cfg.Headers[name] = value
+	}
+
+	scanTargets, err := parseTargets(args)
+	if err != nil {
+		return err
+	}
+	for _, path := range importArgs {
+		imported, err := targets.ImportFile(path)
+		if err != nil {
+			return err
+		}
+		scanTargets = append(scanTargets, imported...)
+	}
+	if len(scanTargets) == 0 {
+		return fmt.Errorf("no targets given")
+	}
+
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return err
+		}
+		types.SetTimeFormat(loc, cfg.TimestampFormat == "epoch")
+	} else if cfg.TimestampFormat == "epoch" {
+		types.SetTimeFormat(nil, true)
+	}
+
+	if cfg.ProtoDir != "" {
+		if err := grpcprobe.LoadProtoDir(cfg.ProtoDir); err != nil {
+			return err
+		}
+	}
+
+	if cfg.WordlistFile != "" {
+		learned, err := wordlist.Load(cfg.WordlistFile, 0)
+		if err != nil {
+			return err
+		}
+		learnedpaths.SetPaths(learned)
+	}
+
+	if cfg.PortProfile != "" {
+		if err := knowledge.SetPortProfile(cfg.PortProfile); err != nil {
+			return err
+		}
+	}
+
+	if cfg.CrawlWorkers > 0 || cfg.CrawlPerHost > 0 || cfg.CrawlDelay > 0 {
+		crawl.SetOptions(crawler.Options{
+			Workers: cfg.CrawlWorkers,
+			PerHost: cfg.CrawlPerHost,
+			Delay:   cfg.CrawlDelay,
+		})
+	}
+
+	if cfg.MetricsAddr != "" {
+		metrics.Serve(cfg.MetricsAddr)
+	}
+
+	if cfg.OTLPEndpoint != "" {
+		tracing.Enable(cfg.OTLPEndpoint)
+		defer func() {
+			if err := tracing.Flush(context.Background()); err != nil {
+				fmt.Fprintln(os.Stderr, "vespasian:", err)
+			}
+		}()
+	}
+
+	if cfg.UploadSSE != "" {
+		objstore.SetSSE(cfg.UploadSSE)
+	}
+
+	for name, options := range cfg.ProbeOptions {
+		p, ok := probe.Get(name)
+		if !ok {
+			return fmt.Errorf("probe_option names unknown probe %q", name)
+		}
+		configurable, ok := p.(probe.Configurable)
+		if !ok {
+			return fmt.Errorf("probe %q takes no options", name)
+		}
+		if err := configurable.Configure(options); err != nil {
+			return err
+		}
+	}
+
+	if cfg.SOAPInvoke {
+		soap.SetInvoke(true)
+	}
+
+	if cfg.GraphQLSchemaDir != "" {
+		graphqlprobe.SetSchemaDir(cfg.GraphQLSchemaDir)
+	}
+
+	for _, spec := range cfg.Plugins {
+		p, err := plugin.New(spec.Name, spec.Command)
+		if err != nil {
+			return err
+		}
+		probe.Register(p)
+	}
+
+	var sockets map[string]string
+	for _, target := range scanTargets {
+		if target.Socket == "" {
+			continue
+		}
+		if sockets == nil {
+			sockets = map[string]string{}
+		}
+		sockets[target.Host] = target.Socket
+	}
+	var dialCommands map[string][]string
+	for _, dialer := range cfg.Dialers {
+		if dialCommands == nil {
+			dialCommands = map[string][]string{}
+		}
+		dialCommands[dialer.Host] = strings.Fields(dialer.Command)
+	}
+
+	clientOpts := client.Options{
+		Timeout:            cfg.Timeout,
+		InsecureSkipVerify: cfg.Insecure,
+		MaxBodySize:        cfg.MaxBodySize,
+		HTTPVersion:        cfg.HTTPVersion,
+		Headers:            cfg.Headers,
+		Sockets:            sockets,
+		DialCommands:       dialCommands,
+		PinPolicy:          cfg.TLSPinning,
+		CookieJar:          cfg.CookieJar,
+		Retry: client.RetryPolicy{
+			MaxAttempts: cfg.RetryAttempts,
+			Backoff:     cfg.RetryBackoff,
+			MaxBackoff:  cfg.RetryMaxBackoff,
+			Jitter:      cfg.RetryJitter,
+			Statuses:    cfg.RetryStatuses,
+		},
+	}
+	for _, cookie := range cfg.Cookies {
+		clientOpts.Cookies = append(clientOpts.Cookies, client.SeedCookie{
+			URL:   cookie.URL,
+			Name:  cookie.Name,
+			Value: cookie.Value,
+		})
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return fmt.Errorf("client_cert and client_key must be set together")
+		}
+		cert, err := client.LoadClientCertificate(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return err
+		}
+		clientOpts.ClientCertificate = cert
+	}
+	if cfg.NTLMUser != "" {
+		domain, user := "", cfg.NTLMUser
+		if d, u, ok := strings.Cut(cfg.NTLMUser, `\`); ok {
+			domain, user = d, u
+		}
+		clientOpts.NTLM = &ntlm.Credentials{
+			Domain:   domain,
+			Username: user,
+			Password: cfg.NTLMPassword,
+		}
+	}
+	if cfg.LoginURL != "" {
+		// A login is pointless if the session cookie it sets is dropped.
+		clientOpts.CookieJar = true
+	}
+	httpClient := client.New(clientOpts)
+
+	if cfg.LoginURL != "" {
+		token, err := (&login.Sequence{
+			URL:         cfg.LoginURL,
+			Method:      cfg.LoginMethod,
+			Form:        cfg.LoginForm,
+			Body:        cfg.LoginBody,
+			ContentType: cfg.LoginContentType,
+			Success:     cfg.LoginSuccess,
+			TokenPath:   cfg.LoginTokenPath,
+		}).Execute(context.Background(), httpClient)
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			httpClient.SetHeader("Authorization", "Bearer "+token)
+		}
+	}
+
+	var recorder *har.Recorder
+	if cfg.HARFile != "" {
+		recorder = har.NewRecorder(cfg.HARMaxBody)
+		httpClient.SetRecorder(recorder)
+	}
+
+	if cfg.SubdomainEnum {
+		scanTargets, err = expandSubdomains(httpClient, scanTargets)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.PortScan {
+		scanTargets = portscan.Expand(context.Background(), scanTargets, portscan.Options{
+			Ports:   cfg.PortScanPorts,
+			Timeout: cfg.Timeout,
+		})
+	}
+
+	if cfg.ServiceDetect {
+		servicedetect.DetectAll(context.Background(), scanTargets, cfg.Timeout)
+	}
+
+	var store *artifact.Store
+	if cfg.ArtifactDir != "" {
+		store, err = artifact.NewStore(cfg.ArtifactDir)
+		if err != nil {
+			return err
+		}
+		store.SetLimits(artifact.Limits{
+			MaxEntries:    cfg.ArtifactMaxEntries,
+			MaxEntrySize:  cfg.ArtifactMaxEntrySize,
+			MaxTotalBytes: cfg.ArtifactMaxBytes,
+		})
+	}
+
+	methodPolicy, err := scan.ParseMethodPolicy(cfg.VerifyMethods)
+	if err != nil {
+		return err
+	}
+
+	var progress scan.Progress
+	if cfg.Progress {
+		progress = &scan.TerminalProgress{W: os.Stderr}
+	}
+
+	var onTargetDone func(scan.TargetSummary)
+	if cfg.Summaries {
+		onTargetDone = (&scan.MarkdownSummary{W: os.Stderr}).TargetDone
+	}
+
+	probes, err := selectProbes(cfg.Probes, cfg.DisabledProbes)
+	if err != nil {
+		return err
+	}
+
+	var dedupePatterns []*regexp.Regexp
+	for _, pattern := range cfg.DedupePatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		dedupePatterns = append(dedupePatterns, compiled)
+	}
+
+	var tagRules []scan.TagRule
+	for _, rule := range cfg.TagRules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return err
+		}
+		tagRules = append(tagRules, scan.TagRule{Pattern: pattern, Tags: rule.Tags})
+	}
+
+	var enricher *enrich.Enricher
+	if cfg.Enrich || cfg.EnrichAPI != "" {
+		enricher = enrich.New(cfg.EnrichAPI)
+	}
+
+	var policyRules []policy.Rule
+	if cfg.PolicyFile != "" {
+		policyRules, err = policy.Load(cfg.PolicyFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	scanner := scan.New(scan.Options{
+		Client:          httpClient,
+		Probes:          probes,
+		Concurrency:     cfg.Concurrency,
+		Store:           store,
+		MinConfidence:   cfg.MinConfidence,
+		TracePath:       cfg.TraceFile,
+		MethodPolicy:    methodPolicy,
+		ClassifyAuth:    cfg.ClassifyAuth,
+		DiscoverMethods: cfg.DiscoverMethods,
+		VersionSweep:    cfg.VersionSweep,
+		Progress:        progress,
+		Dedupe:          cfg.Dedupe,
+		DedupePatterns:  dedupePatterns,
+		TagRules:        tagRules,
+		FilterTags:      cfg.FilterTags,
+		OnTargetDone:    onTargetDone,
+		SampleThreshold: cfg.SampleThreshold,
+		SampleDir:       cfg.SampleDir,
+		Enricher:        enricher,
+		PolicyRules:     policyRules,
+	})
+	ctx := context.Background()
+	var result *types.ScanResult
+	if cfg.Stream {
+		events := make(chan types.Endpoint)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			enc := json.NewEncoder(os.Stdout)
+			for endpoint := range events {
+				enc.Encode(endpoint)
+			}
+		}()
+		result, err = scanner.ScanStream(ctx, scanTargets, events)
+		<-done
+		if err != nil {
+			return err
+		}
+	} else {
+		result, err = scanner.Scan(ctx, scanTargets)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.WordlistFile != "" {
+		if err := wordlist.Update(cfg.WordlistFile, result.Endpoints); err != nil {
+			return err
+		}
+	}
+
+	if recorder != nil {
+		if err := recorder.WriteFile(cfg.HARFile); err != nil {
+			return err
+		}
+	}
+
+	if cfg.HistoryFile != "" {
+		store, err := history.Open(cfg.HistoryFile)
+		if err != nil {
+			return err
+		}
+		store.Update(result.Endpoints, time.Now())
+		if err := store.Save(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.StoreFile != "" {
+		db, err := scanstore.Open(cfg.StoreFile)
+		if err != nil {
+			return err
+		}
+		db.AppendScan(result)
+		if err := db.Save(); err != nil {
+			return err
+		}
+	}
+
+	// Notify after outputs are written so the webhook can point at the
+	// report; a webhook failure must not discard a finished scan.
+	if cfg.WebhookURL != "" {
+		defer func() {
+			notifier := &notify.Notifier{
+				URL:   cfg.WebhookURL,
+				Slack: cfg.WebhookFormat == "slack",
+				On:    cfg.WebhookOn,
+			}
+			reportPath := cfg.Output
+			for _, d := range cfg.Destinations {
+				if d.Path != "" {
+					reportPath = d.Path
+					break
+				}
+			}
+			if err := notifier.ScanDone(ctx, httpClient, result, reportPath); err != nil {
+				fmt.Fprintln(os.Stderr, "vespasian:", err)
+			}
+		}()
+	}
+
+	destinations := []output.Destination{{Format: cfg.Format, Path: cfg.Output}}
+	if len(cfg.Destinations) > 0 {
+		destinations = destinations[:0]
+		for _, d := range cfg.Destinations {
+			destinations = append(destinations, output.Destination{Format: d.Format, Path: d.Path})
+		}
+	}
+	// Streamed findings went to stdout already; only file destinations
+	// still get the final formatted result.
+	if cfg.Stream {
+		kept := destinations[:0]
+		for _, d := range destinations {
+			if d.Path != "" {
+				kept = append(kept, d)
+			}
+		}
+		destinations = kept
+	}
+	if len(destinations) == 0 {
+		return nil
+	}
+	if err := output.WriteAll(destinations, os.Stdout, result); err != nil {
+		// Never lose a finished scan to an output bug: dump the raw
+		// results as NDJSON and report both paths.
+		recovery := "vespasian-recovery.ndjson"
+		if cfg.Output != "" {
+			recovery = cfg.Output + ".recovery.ndjson"
+		}
+		if rerr := output.WriteRecovery(recovery, result); rerr != nil {
+			return fmt.Errorf("%v; recovery dump also failed: %w", err, rerr)
+		}
+		return fmt.Errorf("%w (raw results saved to %s)", err, recovery)
+	}
+	return nil
+}
+
+// runHistory answers lifetime queries against an endpoint history file.
+// With an endpoint argument it prints the matching records; without one it
+// prints the weekly churn report.
+func runHistory(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("no history file given")
+	}
+	store, err := history.Open(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		records := store.Lookup(args[1])
+		if len(records) == 0 {
+			return fmt.Errorf("no endpoint matching %q", args[1])
+		}
+		for _, record := range records {
+			fmt.Printf("%s %s\n  first seen %s, last seen %s, %d scans\n",
+				record.Method, record.URL, record.FirstSeen.Format(time.RFC3339), record.LastSeen.Format(time.RFC3339), record.Scans)
+		}
+		return nil
+	}
+
+	churn := store.Churn()
+	if len(churn) == 0 {
+		fmt.Println("no history recorded")
+		return nil
+	}
+	for _, week := range churn {
+		fmt.Printf("%s  +%d new  -%d removed\n", week.Week, week.New, week.Removed)
+	}
+	return nil
+}
+
+// runQuery lists historical findings from a scan store. Without filters
+// it summarizes the recorded scans; --diff compares two of them.
+func runQuery(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("no store file given")
+	}
+	db, err := scanstore.Open(args[0])
+	if err != nil {
+		return err
+	}
+
+	filter := scanstore.Filter{}
+	diffArg := ""
+	filtered := false
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--scan":
+			i++
+			if i >= len(args) {
+				return fmt.Errorf("--scan needs a scan id")
+			}
+			id, err := strconv.Atoi(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid --scan %q", args[i])
+			}
+			filter.ScanID = id
+			filtered = true
+		case "--source":
+			i++
+			if i >= len(args) {
+				return fmt.Errorf("--source needs a probe name")
+			}
+			filter.Source = args[i]
+			filtered = true
+		case "--diff":
+			i++
+			if i >= len(args) {
+				return fmt.Errorf("--diff needs old:new scan ids")
+			}
+			diffArg = args[i]
+		default:
+			filter.URL = args[i]
+			filtered = true
+		}
+	}
+
+	if diffArg != "" {
+		oldID, newID, err := parseDiffArg(diffArg)
+		if err != nil {
+			return err
+		}
+		added, removed := db.Diff(oldID, newID)
+		for _, finding := range added {
+			fmt.Printf("+ %s %s\n", finding.Method, finding.URL)
+		}
+		for _, finding := range removed {
+			fmt.Printf("- %s %s\n", finding.Method, finding.URL)
+		}
+		return nil
+	}
+
+	if !filtered {
+		if len(db.Scans) == 0 {
+			fmt.Println("no scans recorded")
+			return nil
+		}
+		for _, scan := range db.Scans {
+			fmt.Printf("scan %d  %s  %d targets  %d findings\n",
+				scan.ID, scan.StartedAt.Format(time.RFC3339), len(scan.Targets), scan.Findings)
+		}
+		return nil
+	}
+
+	findings := db.Query(filter)
+	if len(findings) == 0 {
+		return fmt.Errorf("no findings match")
+	}
+	for _, finding := range findings {
+		fmt.Printf("scan %d  %s %s  (%s, %.2f)\n",
+			finding.ScanID, finding.Method, finding.URL, finding.Source, finding.Confidence)
+	}
+	return nil
+}
+
+// parseDiffArg splits "old:new" into the two scan IDs to compare.
+func parseDiffArg(arg string) (int, int, error) {
+	oldPart, newPart, ok := strings.Cut(arg, ":")
+	oldID, errOld := strconv.Atoi(oldPart)
+	newID, errNew := strconv.Atoi(newPart)
+	if !ok || errOld != nil || errNew != nil {
+		return 0, 0, fmt.Errorf("invalid --diff %q, want old:new scan ids", arg)
+	}
+	return oldID, newID, nil
+}
+
+// expandSubdomains enumerates subdomains of each port-less target and
+// appends any new hosts as additional targets.
+func expandSubdomains(c *client.Client, targets []types.Target) ([]types.Target, error) {
+	enumerator := subdomains.New(c)
+	seen := map[string]bool{}
+	for _, target := range targets {
+		seen[target.Host] = true
+	}
+	expanded := targets
+	for _, target := range targets {
+		if target.Port != 0 {
+			continue
+		}
+		found, err := enumerator.Enumerate(context.Background(), target.Host)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range found {
+			if seen[t.Host] {
+				continue
+			}
+			seen[t.Host] = true
+			expanded = append(expanded, t)
+		}
+	}
+	return expanded, nil
+}
+
+// selectProbes resolves the configured probe enablement into a probe set.
+// nil means "every registered probe" and keeps scan.New's default.
+func selectProbes(enabled, disabled []string) ([]probe.Probe, error) {
+	var probes []probe.Probe
+	if len(enabled) > 0 {
+		for _, name := range enabled {
+			p, ok := probe.Get(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown probe %q", name)
+			}
+			probes = append(probes, p)
+		}
+	} else if len(disabled) > 0 {
+		probes = probe.All()
+	} else {
+		return nil, nil
+	}
+	if len(disabled) == 0 {
+		return probes, nil
+	}
+	skip := map[string]bool{}
+	for _, name := range disabled {
+		skip[name] = true
+	}
+	kept := probes[:0]
+	for _, p := range probes {
+		if !skip[p.Name()] {
+			kept = append(kept, p)
+		}
+	}
+	return kept, nil
+}
+
+func parseTargets(args []string) ([]types.Target, error) {
+	var parsed []types.Target
+	for _, arg := range args {
+		// "@file" imports targets in bulk from an inventory export.
+		if strings.HasPrefix(arg, "@") {
+			imported, err := targets.ImportFile(arg[1:])
+			if err != nil {
+				return nil, err
+			}
+			parsed = append(parsed, imported...)
+			continue
+		}
+		target, err := targets.Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, target)
+	}
+	return parsed, nil
+}
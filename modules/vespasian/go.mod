@@ -0,0 +1,3 @@
+module github.com/praetorian-inc/vespasian
+
+go 1.21
@@ -0,0 +1,153 @@
+// Package testserver simulates a realistic scan target for the
+// conformance suite: a SPA with a script bundle, a Swagger document, a
+// GraphQL service, a SOAP service behind a WSDL, WebSocket endpoints
+// (raw, socket.io, and SockJS), and a soft-404 catch-all that answers
+// every other path with 200. Asserting minimum discovery counts against
+// it lets the orchestrator and detectors be refactored with confidence.
+package testserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Options select which behaviors the simulated target exposes.
+type Options struct {
+	// Introspection controls whether the GraphQL endpoint answers the
+	// introspection query; off simulates hardened deployments, which
+	// still leak field names through "Did you mean" suggestions.
+	Introspection bool
+}
+
+// New starts the simulated target on a random loopback port. Targets
+// parsed from the server's URL carry an explicit scheme and so are
+// accepted by every probe regardless of the port profile. The caller
+// closes the returned server.
+func New(opts Options) *httptest.Server {
+	return httptest.NewServer(handler(opts))
+}
+
+const spaIndex = `<html><head><script src="/assets/app.js"></script></head>` +
+	`<body><a href="/about">About</a><a href="/ws">Live</a></body></html>`
+
+// spaBundle references the fixture's own host so bundle-mined absolute
+// URLs stay in scope whatever port the listener got.
+func spaBundle(host string) string {
+	return `(function(){` +
+		`fetch("/api/users");fetch("/api/orders");` +
+		`var ws=new WebSocket("ws://` + host + `/ws");})();`
+}
+
+const swaggerJSON = `{"openapi":"3.0.0","info":{"title":"Fixture API","version":"1.0"},` +
+	`"paths":{"/api/users":{"get":{},"post":{}},"/api/orders/{id}":{"get":{}}}}`
+
+const introspectionResult = `{"data":{"__schema":{` +
+	`"queryType":{"name":"Query"},"mutationType":{"name":"Mutation"},` +
+	`"types":[` +
+	`{"name":"Query","kind":"OBJECT","fields":[` +
+	`{"name":"user","args":[{"name":"id","type":{"name":null,"kind":"NON_NULL","ofType":{"name":"ID"}}}]},` +
+	`{"name":"orders","args":[]}]},` +
+	`{"name":"Mutation","kind":"OBJECT","fields":[` +
+	`{"name":"createOrder","args":[{"name":"input","type":{"name":"OrderInput","kind":"INPUT_OBJECT"}}]}]}` +
+	`]}}}`
+
+const introspectionDisabled = `{"errors":[{"message":"introspection is disabled"}]}`
+
+// fieldSuggestion mimics graphql-js answering an unknown field with
+// nearby real field names, the leak the suggestion-abuse fallback mines.
+const fieldSuggestion = `{"errors":[{"message":` +
+	`"Cannot query field \"userZq\" on type \"Query\". Did you mean \"user\", \"users\", or \"orders\"?"}]}`
+
+// socketIOHandshake is an Engine.IO open packet as served to a polling
+// transport request.
+const socketIOHandshake = `0{"sid":"fixture0000000000000","upgrades":["websocket"],` +
+	`"pingInterval":25000,"pingTimeout":20000}`
+
+// sockjsInfo is a SockJS /info document advertising websocket upgrade.
+const sockjsInfo = `{"websocket":true,"origins":["*:*"],"cookie_needed":false,"entropy":2147483647}`
+
+func serviceWSDL(host string) string {
+	return `<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"` +
+		` xmlns:tns="http://fixture.invalid/orders" targetNamespace="http://fixture.invalid/orders">` +
+		`<message name="GetOrderRequest"><part name="orderId" type="xsd:string"/></message>` +
+		`<portType name="OrdersPortType"><operation name="GetOrder">` +
+		`<input message="tns:GetOrderRequest"/></operation></portType>` +
+		`<binding name="OrdersBinding" type="tns:OrdersPortType"><operation name="GetOrder">` +
+		`<operation soapAction="urn:GetOrder"/></operation></binding>` +
+		`<service name="Orders"><port name="OrdersPort" binding="tns:OrdersBinding">` +
+		`<address location="http://` + host + `/soap/orders"/></port></service>` +
+		`</definitions>`
+}
+
+const soapFault = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+	`<soap:Body><soap:Fault><faultcode>soap:Client</faultcode>` +
+	`<faultstring>empty body</faultstring></soap:Fault></soap:Body></soap:Envelope>`
+
+// soft404 is served with status 200 for every unknown path. It contains
+// no colons or links so spec parsers and the crawler get nothing from it.
+const soft404 = `<html><body><h1>Page not found</h1></body></html>`
+
+func handler(opts Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			serve(w, "text/html", spaIndex)
+		case "/about":
+			serve(w, "text/html", spaIndex)
+		case "/assets/app.js":
+			serve(w, "application/javascript", spaBundle(r.Host))
+		case "/swagger.json":
+			serve(w, "application/json", swaggerJSON)
+		case "/graphql":
+			serveGraphQL(w, r, opts)
+		case "/service":
+			if r.URL.RawQuery == "wsdl" {
+				serve(w, "text/xml", serviceWSDL(r.Host))
+				return
+			}
+			serve(w, "text/html", soft404)
+		case "/soap/orders":
+			serve(w, "text/xml", soapFault)
+		case "/ws":
+			if strings.Contains(strings.ToLower(r.Header.Get("Upgrade")), "websocket") {
+				w.Header().Set("Sec-WebSocket-Version", "13")
+				w.WriteHeader(http.StatusUpgradeRequired)
+				return
+			}
+			serve(w, "text/html", soft404)
+		case "/socket.io/":
+			if r.URL.Query().Get("EIO") == "" {
+				serve(w, "text/html", soft404)
+				return
+			}
+			serve(w, "text/plain", socketIOHandshake)
+		case "/sockjs/info":
+			serve(w, "application/json", sockjsInfo)
+		default:
+			serve(w, "text/html", soft404)
+		}
+	})
+}
+
+// serveGraphQL answers introspection per the fixture options. With
+// introspection off, unknown-field queries still draw "Did you mean"
+// suggestions, mirroring real graphql-js deployments.
+func serveGraphQL(w http.ResponseWriter, r *http.Request, opts Options) {
+	if opts.Introspection {
+		serve(w, "application/json", introspectionResult)
+		return
+	}
+	body, _ := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if strings.Contains(string(body), "__schema") {
+		serve(w, "application/json", introspectionDisabled)
+		return
+	}
+	serve(w, "application/json", fieldSuggestion)
+}
+
+func serve(w http.ResponseWriter, contentType, body string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Write([]byte(body))
+}
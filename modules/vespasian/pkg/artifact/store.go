@@ -0,0 +1,176 @@
+// Package artifact stores raw evidence retrieved during a scan — API specs,
+// introspection responses, interesting response bodies — and records a
+// SHA-256 hash and retrieval timestamp for each item so report deliverables
+// carry a defensible chain of custody.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Entry describes one stored artifact in the scan manifest.
+type Entry struct {
+	// Name is the caller-supplied identifier, typically the URL the
+	// artifact was retrieved from.
+	Name string `json:"name"`
+	// SHA256 is the hex-encoded digest of the artifact contents.
+	SHA256 string `json:"sha256"`
+	// Size is the artifact length in bytes.
+	Size int64 `json:"size"`
+	// Path is the file holding the contents, relative to the store root.
+	// Empty when the contents were sampled out by the store limits; the
+	// hash and timestamp are still recorded.
+	Path string `json:"path,omitempty"`
+	// Stored reports whether the contents were written to disk.
+	Stored bool `json:"stored"`
+	// RetrievedAt is when the artifact was captured.
+	RetrievedAt types.Time `json:"retrieved_at"`
+}
+
+// Manifest is the chain-of-custody record written alongside stored
+// artifacts at the end of a scan.
+type Manifest struct {
+	GeneratedAt types.Time `json:"generated_at"`
+	Entries     []Entry    `json:"entries"`
+}
+
+// Limits controls how much evidence a Store writes to disk. Artifacts
+// sampled out still get manifest entries with hashes and timestamps, so
+// chain of custody survives even when the raw bytes are not kept.
+type Limits struct {
+	// MaxEntries caps how many artifact bodies are stored; 0 is unlimited.
+	MaxEntries int
+	// MaxEntrySize skips bodies larger than this many bytes; 0 is unlimited.
+	MaxEntrySize int64
+	// MaxTotalBytes stops storing bodies once this much is on disk;
+	// 0 is unlimited.
+	MaxTotalBytes int64
+}
+
+// Store writes artifacts to a directory, content-addressed by hash.
+// A nil *Store is valid and discards everything, so callers can thread a
+// store through unconditionally.
+type Store struct {
+	dir string
+
+	mu         sync.Mutex
+	entries    []Entry
+	limits     Limits
+	stored     int
+	totalBytes int64
+}
+
+// NewStore creates the directory if needed and returns a Store rooted there.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("artifact: creating store directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// SetLimits applies sampling limits; call before the scan starts.
+func (s *Store) SetLimits(limits Limits) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.limits = limits
+	s.mu.Unlock()
+}
+
+// Save hashes data, writes it under the store root if the limits allow,
+// and records a manifest entry either way. Identical contents saved under
+// different names produce separate entries pointing at the same file.
+func (s *Store) Save(name string, data []byte) (Entry, error) {
+	if s == nil {
+		return Entry{}, nil
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	entry := Entry{
+		Name:        name,
+		SHA256:      digest,
+		Size:        int64(len(data)),
+		RetrievedAt: types.NewTime(time.Now().UTC()),
+	}
+
+	s.mu.Lock()
+	store := s.admit(int64(len(data)))
+	if store {
+		s.stored++
+		s.totalBytes += int64(len(data))
+	}
+	s.mu.Unlock()
+
+	if store {
+		entry.Path = digest + ".bin"
+		entry.Stored = true
+		full := filepath.Join(s.dir, entry.Path)
+		if _, err := os.Stat(full); os.IsNotExist(err) {
+			if err := os.WriteFile(full, data, 0o644); err != nil {
+				return Entry{}, fmt.Errorf("artifact: writing %s: %w", entry.Path, err)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.entries = append(s.entries, entry)
+	s.mu.Unlock()
+	return entry, nil
+}
+
+// admit reports whether a body of the given size may be written under the
+// current limits. Callers must hold s.mu.
+func (s *Store) admit(size int64) bool {
+	if s.limits.MaxEntries > 0 && s.stored >= s.limits.MaxEntries {
+		return false
+	}
+	if s.limits.MaxEntrySize > 0 && size > s.limits.MaxEntrySize {
+		return false
+	}
+	if s.limits.MaxTotalBytes > 0 && s.totalBytes+size > s.limits.MaxTotalBytes {
+		return false
+	}
+	return true
+}
+
+// Entries returns a copy of the recorded entries sorted by name.
+func (s *Store) Entries() []Entry {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Entry, len(s.entries))
+	copy(out, s.entries)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// WriteManifest writes manifest.json in the store root covering every
+// artifact saved so far.
+func (s *Store) WriteManifest() error {
+	if s == nil {
+		return nil
+	}
+	manifest := Manifest{
+		GeneratedAt: types.NewTime(time.Now().UTC()),
+		Entries:     s.Entries(),
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, "manifest.json"), data, 0o644)
+}
@@ -0,0 +1,121 @@
+// Package login executes a configured login request before a scan, so
+// probes run with the session it establishes. Cookies the login sets land
+// in the client's jar; a bearer token can be pulled out of the response
+// body by a dotted JSON path and attached to every subsequent request.
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+// Sequence describes one login request and how to judge its outcome.
+type Sequence struct {
+	// URL receives the login request.
+	URL string
+	// Method defaults to POST.
+	Method string
+	// Form holds form fields sent URL-encoded. Body is used instead when
+	// Form is empty.
+	Form map[string]string
+	// Body is the raw request body, typically a JSON credential document.
+	Body string
+	// ContentType overrides the inferred request content type:
+	// form-urlencoded when Form is set, otherwise application/json.
+	ContentType string
+	// Success is a substring the response body must contain for the
+	// login to count; empty accepts any non-error status.
+	Success string
+	// TokenPath is a dotted path ("data.access_token") into the JSON
+	// response naming a bearer token to extract.
+	TokenPath string
+}
+
+// Execute performs the login with c and returns the bearer token named by
+// TokenPath, or "" when no token extraction was configured. Session
+// cookies the response sets persist in c's cookie jar.
+func (s *Sequence) Execute(ctx context.Context, c *client.Client) (string, error) {
+	method := s.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	body := s.Body
+	contentType := s.ContentType
+	if len(s.Form) > 0 {
+		values := url.Values{}
+		for name, value := range s.Form {
+			values.Set(name, value)
+		}
+		body = values.Encode()
+		if contentType == "" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+	} else if contentType == "" && body != "" {
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.URL, strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("login: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("login: %s answered %d", s.URL, resp.StatusCode)
+	}
+	if s.Success != "" && !strings.Contains(string(resp.Body), s.Success) {
+		return "", fmt.Errorf("login: success marker %q not found in response", s.Success)
+	}
+	if s.TokenPath == "" {
+		return "", nil
+	}
+	token, ok := extractToken(resp.Body, s.TokenPath)
+	if !ok {
+		return "", fmt.Errorf("login: token path %q not found in response", s.TokenPath)
+	}
+	return token, nil
+}
+
+// extractToken walks a dotted path through a JSON document. Path segments
+// name object keys; a numeric segment indexes into an array. A leading
+// "$." is tolerated for JSONPath familiarity.
+func extractToken(body []byte, path string) (string, bool) {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return "", false
+	}
+	path = strings.TrimPrefix(path, "$.")
+	for _, segment := range strings.Split(path, ".") {
+		switch node := doc.(type) {
+		case map[string]any:
+			child, ok := node[segment]
+			if !ok {
+				return "", false
+			}
+			doc = child
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", false
+			}
+			doc = node[index]
+		default:
+			return "", false
+		}
+	}
+	token, ok := doc.(string)
+	return token, ok && token != ""
+}
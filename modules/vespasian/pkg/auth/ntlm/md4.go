@@ -0,0 +1,71 @@
+package ntlm
+
+import "encoding/binary"
+
+// md4Sum computes the MD4 digest of data (RFC 1320). MD4 is long broken
+// for integrity purposes but remains a mandatory primitive in NTLM
+// password hashing, and the standard library does not ship it.
+func md4Sum(data []byte) [16]byte {
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+
+	// Pad to 56 mod 64 and append the bit length, little endian.
+	length := len(data)
+	padded := make([]byte, 0, length+72)
+	padded = append(padded, data...)
+	padded = append(padded, 0x80)
+	for len(padded)%64 != 56 {
+		padded = append(padded, 0)
+	}
+	padded = binary.LittleEndian.AppendUint64(padded, uint64(length)<<3)
+
+	var (
+		order1 = [16]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+		order2 = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+		order3 = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+		shift1 = [4]uint{3, 7, 11, 19}
+		shift2 = [4]uint{3, 5, 9, 13}
+		shift3 = [4]uint{3, 9, 11, 15}
+	)
+
+	var x [16]uint32
+	for block := 0; block < len(padded); block += 64 {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(padded[block+i*4:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		// Round 1: F(x,y,z) = (x AND y) OR (NOT x AND z)
+		for j, i := range order1 {
+			s := shift1[j%4]
+			a += ((b & c) | (^b & d)) + x[i]
+			a = a<<s | a>>(32-s)
+			a, b, c, d = d, a, b, c
+		}
+		// Round 2: G(x,y,z) = (x AND y) OR (x AND z) OR (y AND z)
+		for j, i := range order2 {
+			s := shift2[j%4]
+			a += ((b & c) | (b & d) | (c & d)) + x[i] + 0x5a827999
+			a = a<<s | a>>(32-s)
+			a, b, c, d = d, a, b, c
+		}
+		// Round 3: H(x,y,z) = x XOR y XOR z
+		for j, i := range order3 {
+			s := shift3[j%4]
+			a += (b ^ c ^ d) + x[i] + 0x6ed9eba1
+			a = a<<s | a>>(32-s)
+			a, b, c, d = d, a, b, c
+		}
+
+		a += aa
+		b += bb
+		c += cc
+		d += dd
+	}
+
+	var digest [16]byte
+	binary.LittleEndian.PutUint32(digest[0:], a)
+	binary.LittleEndian.PutUint32(digest[4:], b)
+	binary.LittleEndian.PutUint32(digest[8:], c)
+	binary.LittleEndian.PutUint32(digest[12:], d)
+	return digest
+}
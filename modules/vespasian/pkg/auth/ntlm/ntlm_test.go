@@ -0,0 +1,37 @@
+package ntlm
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestMD4Vectors checks the vendored MD4 against the RFC 1320 appendix
+// test suite; NTLM response correctness starts here.
+func TestMD4Vectors(t *testing.T) {
+	vectors := map[string]string{
+		"":                           "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"a":                          "bde52cb31de33e46245e05fbdbd6fb24",
+		"abc":                        "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest":             "d9130a8164549fe818874806e1c7014b",
+		"abcdefghijklmnopqrstuvwxyz": "d79e1c308aa5bbcdeea8ed63df412da9",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789":                   "043f8582f241db351ce627e153e7f0e4",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+	for input, want := range vectors {
+		sum := md4Sum([]byte(input))
+		if got := hex.EncodeToString(sum[:]); got != want {
+			t.Errorf("md4(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+// TestNTOWFv2 checks the NTLMv2 key derivation against the worked
+// example in MS-NLMP section 4.2.2 (user "User", domain "Domain",
+// password "Password").
+func TestNTOWFv2(t *testing.T) {
+	key := ntowfV2(Credentials{Domain: "Domain", Username: "User", Password: "Password"})
+	want := "0c868a403bfd7a93a3001ef22ef02e3f"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("ntowfV2 = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,476 @@
+// Package client provides the shared HTTP client used by all probes. It
+// centralizes timeouts, TLS configuration, and the user agent so probes
+// behave consistently against targets.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/artifact"
+	"github.com/praetorian-inc/vespasian/pkg/auth/ntlm"
+	"github.com/praetorian-inc/vespasian/pkg/metrics"
+)
+
+// DefaultUserAgent identifies vespasian in outbound requests.
+const DefaultUserAgent = "vespasian/1.0"
+
+// DefaultMaxBodySize caps how much of a response body is read into memory.
+// Large Swagger documents, JS bundles, or binary downloads beyond the cap
+// are truncated rather than buffered whole.
+const DefaultMaxBodySize = 10 << 20 // 10 MiB
+
+// Response captures the parts of an HTTP response probes care about after
+// the body has been read and the connection released.
+type Response struct {
+	StatusCode int
+	// Proto is the negotiated protocol version ("HTTP/1.1", "HTTP/2.0").
+	Proto  string
+	Header http.Header
+	Body   []byte
+	// Truncated reports that the body exceeded the client max body size
+	// and Body holds only the first MaxBodySize bytes.
+	Truncated bool
+}
+
+// Client wraps http.Client with probe-friendly helpers.
+type Client struct {
+	httpClient  *http.Client
+	userAgent   string
+	maxBodySize int64
+	ntlm        *ntlm.Credentials
+	store       *artifact.Store
+	retry       RetryPolicy
+	headers     map[string]string
+	recorder    Recorder
+}
+
+// Recorder receives every completed exchange the client buffers, e.g. for
+// HAR export. Implementations must be safe for concurrent use.
+type Recorder interface {
+	Record(req *http.Request, status int, proto string, header http.Header, body []byte, started time.Time, duration time.Duration)
+}
+
+// Options configures a Client.
+type Options struct {
+	Timeout            time.Duration
+	InsecureSkipVerify bool
+	UserAgent          string
+	// MaxBodySize caps buffered response bodies in bytes. Zero means
+	// DefaultMaxBodySize; negative means no limit.
+	MaxBodySize int64
+	// NTLM, when set, answers NTLM and Negotiate challenges with an
+	// NTLMv2 handshake using these credentials.
+	NTLM *ntlm.Credentials
+	// Headers are set on every outbound request: engagement identification
+	// headers, tenant headers, a custom X-Forwarded-For. A probe's own
+	// header of the same name wins.
+	Headers map[string]string
+	// Retry controls retries with exponential backoff and jitter for
+	// transient failures; the zero value sends each request once.
+	Retry RetryPolicy
+	// Transport, when non-nil, replaces the client's network transport
+	// entirely. It is meant for deterministic runs against recorded
+	// traffic; HTTPVersion and InsecureSkipVerify are ignored with it.
+	Transport http.RoundTripper
+	// Sockets maps a target host alias to the Unix domain socket dialed
+	// in its place, so HTTP probes can enumerate socket-exposed APIs
+	// (Docker, kubelet) like any other target.
+	Sockets map[string]string
+	// DialCommands maps a host (or host:port) to a command whose stdio
+	// becomes the connection — typically an SSH tunnel such as
+	// "ssh -W host:port bastion".
+	DialCommands map[string][]string
+	// PinPolicy guards against server certificates changing mid-scan:
+	// the leaf fingerprint observed on first contact with each host is
+	// pinned, and a later change is logged (PinWarn) or fails the
+	// connection (PinAbort). PinOff disables pinning.
+	PinPolicy string
+	// ClientCertificate, when non-nil, is presented during TLS handshakes
+	// so the client can enumerate APIs behind mutual TLS. Build one with
+	// LoadClientCertificate.
+	ClientCertificate *tls.Certificate
+	// HTTPVersion pins the protocol version: "auto" (or empty) negotiates
+	// HTTP/2 via ALPN and falls back to HTTP/1.1, "http/1.1" disables the
+	// upgrade, "h2" refuses to speak anything but HTTP/2 over TLS, and
+	// "h2c" speaks prior-knowledge HTTP/2 in the clear to every target
+	// (gRPC backends and some internal gateways answer nothing else).
+	// Some API gateways only answer properly on h2, so pinning it exposes
+	// endpoints a 1.1-only scan would miss. HTTP/3 needs a QUIC stack
+	// vespasian does not vendor and is not supported.
+	HTTPVersion string
+	// CookieJar keeps session cookies for the life of the scan, so an app
+	// that sets a session on the landing page stops 302ing every probe to
+	// its login form. The jar's domain matching keeps each target's
+	// cookies to itself.
+	CookieJar bool
+	// Cookies pre-seeds the jar (implicitly enabling it) with sessions
+	// obtained out of band, e.g. copied from an authenticated browser.
+	Cookies []SeedCookie
+}
+
+// SeedCookie is a cookie loaded into the jar before the scan starts. URL
+// scopes the cookie: it is sent on requests the jar's matching rules
+// consider in scope for that URL's host and path.
+type SeedCookie struct {
+	URL   string
+	Name  string
+	Value string
+}
+
+// New returns a Client ready for probe use.
+func New(opts Options) *Client {
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	var transport http.RoundTripper = opts.Transport
+	if transport == nil {
+		dialer := newCachingDialer(opts.Timeout, opts.Sockets, opts.DialCommands)
+		t := &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify},
+			DialContext:     dialer.DialContext,
+		}
+		if opts.ClientCertificate != nil {
+			t.TLSClientConfig.Certificates = []tls.Certificate{*opts.ClientCertificate}
+		}
+		if opts.PinPolicy != PinOff {
+			t.TLSClientConfig.VerifyConnection = newPinner(opts.PinPolicy).verify
+		}
+		switch opts.HTTPVersion {
+		case "", "auto":
+			t.ForceAttemptHTTP2 = true
+		case "h2":
+			t.ForceAttemptHTTP2 = true
+			t.TLSClientConfig.NextProtos = []string{"h2"}
+		case "http/1.1":
+			t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		case "h2c":
+			transport = newH2CTransport(t, dialer)
+		}
+		if transport == nil {
+			transport = t
+		}
+	}
+	var jar http.CookieJar
+	if opts.CookieJar || len(opts.Cookies) > 0 {
+		jar = newJar(opts.Cookies)
+	}
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: transport,
+			Jar:       jar,
+		},
+		userAgent:   opts.UserAgent,
+		maxBodySize: opts.MaxBodySize,
+		ntlm:        opts.NTLM,
+		retry:       opts.Retry,
+		headers:     opts.Headers,
+	}
+}
+
+// newJar builds the scan cookie jar and loads any pre-seeded cookies into
+// it. Seeds with an unparsable URL are skipped; a typoed config line
+// should not take down the whole scan.
+func newJar(seeds []SeedCookie) http.CookieJar {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil
+	}
+	for _, seed := range seeds {
+		u, err := url.Parse(seed.URL)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		jar.SetCookies(u, []*http.Cookie{{Name: seed.Name, Value: seed.Value}})
+	}
+	return jar
+}
+
+// SetHeader adds a header sent on every subsequent request, e.g. a bearer
+// token obtained by a login sequence. It is meant for scan setup and is
+// not safe to call once probes are running.
+func (c *Client) SetHeader(name, value string) {
+	if c.headers == nil {
+		c.headers = map[string]string{}
+	}
+	c.headers[name] = value
+}
+
+// SetArtifactStore makes the client record every response body it reads as
+// an evidence artifact, keyed by request URL. A nil store disables capture.
+func (c *Client) SetArtifactStore(store *artifact.Store) {
+	c.store = store
+}
+
+// SetRecorder makes the client report every buffered exchange to r. A nil
+// recorder disables recording.
+func (c *Client) SetRecorder(r Recorder) {
+	c.recorder = r
+}
+
+// Get fetches url and returns the full response.
+func (c *Client) Get(ctx context.Context, url string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post sends body to url with the given content type.
+func (c *Client) Post(ctx context.Context, url, contentType string, body io.Reader) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// GetNoRedirect fetches url without following redirects, so callers can
+// walk a redirect chain hop by hop. 3xx responses are returned as-is with
+// their Location header intact.
+func (c *Client) GetNoRedirect(ctx context.Context, url string) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// A shallow copy shares the transport (TLS, dial cache, pinned
+	// version) and the cookie jar but stops at the first response.
+	noRedirect := *c.httpClient
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for name, value := range c.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	out := &Response{StatusCode: resp.StatusCode, Proto: resp.Proto, Header: resp.Header}
+	reader := io.Reader(resp.Body)
+	if c.maxBodySize > 0 {
+		reader = io.LimitReader(resp.Body, c.maxBodySize)
+	}
+	out.Body, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Do executes req, applying the client user agent if none is set. The body
+// is read through the client size cap; bodies beyond it are truncated and
+// flagged on the Response rather than buffered whole.
+func (c *Client) Do(req *http.Request) (*Response, error) {
+	started := time.Now()
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := &Response{StatusCode: resp.StatusCode, Proto: resp.Proto, Header: resp.Header}
+	reader := io.Reader(resp.Body)
+	if c.maxBodySize > 0 {
+		// Read one byte past the cap so truncation is detectable.
+		reader = io.LimitReader(resp.Body, c.maxBodySize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if c.maxBodySize > 0 && int64(len(body)) > c.maxBodySize {
+		body = body[:c.maxBodySize]
+		out.Truncated = true
+	}
+	out.Body = body
+	if c.recorder != nil {
+		c.recorder.Record(req, resp.StatusCode, resp.Proto, resp.Header, body, started, time.Since(started))
+	}
+	if c.store != nil && len(body) > 0 {
+		if _, err := c.store.Save(req.URL.String(), body); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Stream executes req and hands the raw body reader to fn instead of
+// buffering it, for callers that process large downloads incrementally.
+// The reader is still bounded by the client size cap. The returned
+// Response carries status and headers only.
+func (c *Client) Stream(req *http.Request, fn func(io.Reader) error) (*Response, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	reader := io.Reader(resp.Body)
+	if c.maxBodySize > 0 {
+		reader = io.LimitReader(resp.Body, c.maxBodySize)
+	}
+	if err := fn(reader); err != nil {
+		return nil, err
+	}
+	return &Response{StatusCode: resp.StatusCode, Proto: resp.Proto, Header: resp.Header}, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for name, value := range c.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+	resp, err := c.doRetry(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.ntlm != nil {
+		if scheme := ntlmScheme(resp.Header); scheme != "" {
+			return c.doNTLM(req, resp, scheme)
+		}
+	}
+	return resp, nil
+}
+
+// httpRequests counts every request attempt by response status class, so
+// a scrape shows both scan volume and how targets are answering.
+var httpRequests = metrics.NewCounter("vespasian_http_requests_total",
+	"HTTP request attempts, by response status class.", "class")
+
+// doOnce issues a single request attempt with no retry handling.
+func (c *Client) doOnce(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		httpRequests.Inc("error")
+	} else {
+		httpRequests.Inc(strconv.Itoa(resp.StatusCode/100) + "xx")
+	}
+	return resp, err
+}
+
+// doNTLM completes the three-leg NTLM handshake after an initial 401.
+// initial is consumed and closed.
+func (c *Client) doNTLM(req *http.Request, initial *http.Response, scheme string) (*http.Response, error) {
+	drain(initial)
+
+	// Leg 1: negotiate.
+	negotiateReq, err := cloneRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	negotiateReq.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(ntlm.Negotiate()))
+	resp, err := c.httpClient.Do(negotiateReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	// Leg 2: the server's challenge arrives in WWW-Authenticate.
+	token := challengeToken(resp.Header, scheme)
+	drain(resp)
+	if token == "" {
+		return nil, fmt.Errorf("ntlm: server sent no %s challenge", scheme)
+	}
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("ntlm: decoding challenge: %w", err)
+	}
+	challenge, err := ntlm.ParseChallenge(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leg 3: authenticate.
+	authMsg, err := ntlm.Authenticate(*c.ntlm, challenge)
+	if err != nil {
+		return nil, err
+	}
+	authReq, err := cloneRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	authReq.Header.Set("Authorization", scheme+" "+base64.StdEncoding.EncodeToString(authMsg))
+	return c.httpClient.Do(authReq)
+}
+
+// ntlmScheme returns the challenge scheme to answer, preferring NTLM over
+// Negotiate, or "" when the server offered neither.
+func ntlmScheme(header http.Header) string {
+	negotiate := ""
+	for _, challenge := range header.Values("Www-Authenticate") {
+		scheme := strings.SplitN(strings.TrimSpace(challenge), " ", 2)[0]
+		switch {
+		case strings.EqualFold(scheme, "NTLM"):
+			return "NTLM"
+		case strings.EqualFold(scheme, "Negotiate"):
+			negotiate = "Negotiate"
+		}
+	}
+	return negotiate
+}
+
+// challengeToken extracts the base64 token from a "<scheme> <token>"
+// WWW-Authenticate value.
+func challengeToken(header http.Header, scheme string) string {
+	for _, challenge := range header.Values("Www-Authenticate") {
+		value := strings.TrimSpace(challenge)
+		if len(value) > len(scheme) && strings.EqualFold(value[:len(scheme)], scheme) {
+			return strings.TrimSpace(value[len(scheme):])
+		}
+	}
+	return ""
+}
+
+// cloneRequest duplicates req with a fresh body so it can be resent.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("ntlm: request body cannot be replayed")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// drain discards and closes a response body so its connection is reusable,
+// which NTLM's connection-oriented handshake depends on.
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
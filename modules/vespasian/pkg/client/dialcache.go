@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// dnsCacheTTL is how long resolved addresses are reused. The stdlib
+// resolver does not surface record TTLs, so a short fixed window stands
+// in for them; a scan against a host whose records rotate faster than
+// this would be misdirected anyway.
+const dnsCacheTTL = 5 * time.Minute
+
+// dialFailureTTL is how long an unreachable address is memoized. Probes
+// fan many requests out to the same host, and a dead host should fail
+// each of them immediately rather than once per connection timeout.
+const dialFailureTTL = 2 * time.Minute
+
+// cachingDialer wraps net.Dialer with an in-process DNS cache and a
+// negative dial cache, shared by every probe through the client
+// transport. Large multi-probe scans would otherwise repeat the same
+// lookups and dead-host dials for each probe/path combination.
+type cachingDialer struct {
+	dialer   net.Dialer
+	resolver *net.Resolver
+	// sockets maps a target host alias to the Unix domain socket dialed
+	// in its place; commands maps a host (or host:port) to a dial command
+	// whose stdio becomes the connection.
+	sockets  map[string]string
+	commands map[string][]string
+
+	mu       sync.Mutex
+	dns      map[string]dnsEntry
+	failures map[string]failureEntry
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+type failureEntry struct {
+	err     error
+	expires time.Time
+}
+
+func newCachingDialer(timeout time.Duration, sockets map[string]string, commands map[string][]string) *cachingDialer {
+	return &cachingDialer{
+		dialer:   net.Dialer{Timeout: timeout},
+		resolver: net.DefaultResolver,
+		sockets:  sockets,
+		commands: commands,
+		dns:      map[string]dnsEntry{},
+		failures: map[string]failureEntry{},
+	}
+}
+
+// DialContext resolves through the DNS cache and refuses addresses with a
+// live failure entry before touching the network.
+func (d *cachingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return d.dialer.DialContext(ctx, network, address)
+	}
+
+	if path, ok := d.sockets[host]; ok {
+		return d.dialer.DialContext(ctx, "unix", path)
+	}
+	if argv, ok := d.commands[address]; ok {
+		return dialCommand(argv)
+	}
+	if argv, ok := d.commands[host]; ok {
+		return dialCommand(argv)
+	}
+
+	if err := d.knownFailure(address); err != nil {
+		return nil, err
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		d.recordFailure(address, err)
+		return nil, err
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, lastErr
+		}
+	}
+	d.recordFailure(address, lastErr)
+	return nil, lastErr
+}
+
+// lookup resolves host, serving from cache while the entry is live. IP
+// literals bypass the resolver entirely.
+func (d *cachingDialer) lookup(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	d.mu.Lock()
+	entry, ok := d.dns[host]
+	d.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	ips, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+
+	d.mu.Lock()
+	d.dns[host] = dnsEntry{addrs: addrs, expires: time.Now().Add(dnsCacheTTL)}
+	d.mu.Unlock()
+	return addrs, nil
+}
+
+// knownFailure returns the memoized dial error for address, if one is
+// still live.
+func (d *cachingDialer) knownFailure(address string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry, ok := d.failures[address]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(d.failures, address)
+		return nil
+	}
+	return entry.err
+}
+
+func (d *cachingDialer) recordFailure(address string, err error) {
+	if err == nil {
+		return
+	}
+	d.mu.Lock()
+	d.failures[address] = failureEntry{err: err, expires: time.Now().Add(dialFailureTTL)}
+	d.mu.Unlock()
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// commandConn runs a user-configured dial command — typically an SSH
+// tunnel like "ssh -W host:port bastion" — and exposes its stdio as a
+// net.Conn, so socket- and tunnel-exposed APIs are reachable through the
+// ordinary HTTP probes.
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// dialCommand starts argv and wires its stdio into a connection.
+func dialCommand(argv []string) (net.Conn, error) {
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("empty dial command")
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("dial command %q: %w", strings.Join(argv, " "), err)
+	}
+	return &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}, nil
+}
+
+// Read implements net.Conn.
+func (c *commandConn) Read(p []byte) (int, error) { return c.stdout.Read(p) }
+
+// Write implements net.Conn.
+func (c *commandConn) Write(p []byte) (int, error) { return c.stdin.Write(p) }
+
+// Close implements net.Conn, tearing the command down.
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		if c.cmd.Process != nil {
+			c.cmd.Process.Kill()
+		}
+		c.closeErr = c.cmd.Wait()
+	})
+	return c.closeErr
+}
+
+// LocalAddr implements net.Conn.
+func (c *commandConn) LocalAddr() net.Addr { return commandAddr(c.cmd.Path) }
+
+// RemoteAddr implements net.Conn.
+func (c *commandConn) RemoteAddr() net.Addr { return commandAddr(c.cmd.Path) }
+
+// Deadlines cannot be pushed into an external process; the client-level
+// request timeout still bounds each exchange.
+func (c *commandConn) SetDeadline(time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(time.Time) error { return nil }
+
+// commandAddr is the nominal address of a command-backed connection.
+type commandAddr string
+
+func (a commandAddr) Network() string { return "cmd" }
+func (a commandAddr) String() string  { return string(a) }
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// newH2CTransport builds a transport speaking prior-knowledge cleartext
+// HTTP/2 (h2c, RFC 9113 section 3.3) using only net/http's bundled
+// HTTP/2 client. That client engages solely for *tls.Conn connections
+// that negotiated "h2", so each dial loops through an in-process TLS
+// tunnel: the transport handshakes a real tls.Conn against a local
+// tls.Server over a pipe, and the server half relays the decrypted
+// HTTP/2 frames to the remote socket in the clear. Request schemes are
+// rewritten so net/http takes its TLS dial path. No Upgrade dance is
+// attempted: targets that only speak HTTP/1.1 fail the connection,
+// which is the point of pinning a version.
+func newH2CTransport(t *http.Transport, dialer *cachingDialer) http.RoundTripper {
+	t.ForceAttemptHTTP2 = true
+	t.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		remote, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return loopbackTLS(ctx, remote)
+	}
+	return &h2cTransport{base: t}
+}
+
+// h2cTransport rewrites http:// requests to https:// so the underlying
+// transport routes them through DialTLSContext; the response is handed
+// back carrying the original request so callers never see the rewritten
+// scheme.
+type h2cTransport struct {
+	base *http.Transport
+}
+
+func (h *h2cTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" {
+		return h.base.RoundTrip(req)
+	}
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = "https"
+	resp, err := h.base.RoundTrip(rewritten)
+	if resp != nil {
+		resp.Request = req
+	}
+	return resp, err
+}
+
+// loopbackTLS returns the client half of a local TLS session whose
+// server half strips the encryption and relays the plaintext to remote.
+// Both halves live in this process; nothing TLS touches the network.
+func loopbackTLS(ctx context.Context, remote net.Conn) (net.Conn, error) {
+	certificate, err := loopbackCertificate()
+	if err != nil {
+		remote.Close()
+		return nil, err
+	}
+	clientSide, serverSide := net.Pipe()
+	server := tls.Server(serverSide, &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		NextProtos:   []string{"h2"},
+	})
+	go func() {
+		defer remote.Close()
+		defer server.Close()
+		if err := server.HandshakeContext(ctx); err != nil {
+			return
+		}
+		done := make(chan struct{})
+		go func() {
+			io.Copy(server, remote)
+			server.Close()
+			close(done)
+		}()
+		io.Copy(remote, server)
+		remote.Close()
+		<-done
+	}()
+	return tls.Client(clientSide, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         "localhost",
+		NextProtos:         []string{"h2"},
+	}), nil
+}
+
+var (
+	loopbackOnce sync.Once
+	loopbackCert tls.Certificate
+	loopbackErr  error
+)
+
+// loopbackCertificate self-signs a throwaway certificate for the local
+// tunnel, once per process. It never leaves the process and verifies
+// nothing; it exists because a TLS server must present one.
+func loopbackCertificate() (tls.Certificate, error) {
+	loopbackOnce.Do(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			loopbackErr = err
+			return
+		}
+		template := x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "vespasian h2c loopback"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(24 * 365 * time.Hour),
+			DNSNames:     []string{"localhost"},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+		if err != nil {
+			loopbackErr = err
+			return
+		}
+		loopbackCert = tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	})
+	return loopbackCert, loopbackErr
+}
@@ -0,0 +1,111 @@
+package client_test
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+// http2Preface is the fixed byte sequence every HTTP/2 connection must
+// open with (RFC 9113 section 3.4).
+const http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+// TestH2CSendsPriorKnowledgePreface asserts an h2c-pinned client opens
+// plain TCP connections with the HTTP/2 connection preface rather than
+// an HTTP/1.1 request line or a TLS ClientHello.
+func TestH2CSendsPriorKnowledgePreface(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	opening := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, len(http2Preface))
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _ := conn.Read(buf)
+		opening <- string(buf[:n])
+	}()
+
+	c := client.New(client.Options{Timeout: 2 * time.Second, HTTPVersion: "h2c"})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	// The fixture never answers, so the request itself fails; only the
+	// bytes on the wire matter.
+	c.Get(ctx, "http://"+listener.Addr().String()+"/")
+
+	select {
+	case got := <-opening:
+		if got != http2Preface {
+			t.Errorf("connection opened with %q, want the HTTP/2 preface", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no connection received")
+	}
+}
+
+// TestH2CRoundTrip completes a request against a server that only
+// understands HTTP/2. The standard library cannot serve cleartext h2
+// directly, so a relay forwards the client's cleartext frames into an
+// httptest HTTP/2 TLS server; the frames themselves are identical
+// either way.
+func TestH2CRoundTrip(t *testing.T) {
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("over " + r.Proto))
+	}))
+	backend.EnableHTTP2 = true
+	backend.StartTLS()
+	defer backend.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				upstream, err := tls.Dial("tcp", backend.Listener.Addr().String(),
+					&tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}})
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}()
+		}
+	}()
+
+	c := client.New(client.Options{Timeout: 5 * time.Second, HTTPVersion: "h2c"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := c.Get(ctx, "http://"+listener.Addr().String()+"/")
+	if err != nil {
+		t.Fatalf("h2c request failed: %v", err)
+	}
+	if resp.Proto != "HTTP/2.0" {
+		t.Errorf("negotiated %s, want HTTP/2.0", resp.Proto)
+	}
+	if string(resp.Body) != "over HTTP/2.0" {
+		t.Errorf("body = %q, want the h2 handler's answer", resp.Body)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadClientCertificate builds the certificate presented during mutual
+// TLS handshakes. cert and key are each either a PEM file path or an
+// inline PEM block, detected by the "-----BEGIN" marker — deployment
+// tooling hands credentials around both ways.
+func LoadClientCertificate(cert, key string) (*tls.Certificate, error) {
+	certPEM, err := pemBytes(cert)
+	if err != nil {
+		return nil, fmt.Errorf("client certificate: %w", err)
+	}
+	keyPEM, err := pemBytes(key)
+	if err != nil {
+		return nil, fmt.Errorf("client key: %w", err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("client certificate: %w", err)
+	}
+	return &pair, nil
+}
+
+// pemBytes returns value itself when it is inline PEM, otherwise the
+// contents of the file it names.
+func pemBytes(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Pinning policies. PinWarn logs a changed certificate and carries on;
+// PinAbort fails the connection, stopping a long engagement from quietly
+// continuing through transparent interception or an environment mix-up.
+const (
+	PinOff   = ""
+	PinWarn  = "warn"
+	PinAbort = "abort"
+)
+
+// pinner records the leaf certificate fingerprint observed on first
+// contact with each host and enforces it on every later handshake.
+type pinner struct {
+	policy string
+
+	mu   sync.Mutex
+	seen map[string]string
+}
+
+func newPinner(policy string) *pinner {
+	return &pinner{policy: policy, seen: map[string]string{}}
+}
+
+// verify implements tls.Config.VerifyConnection.
+func (p *pinner) verify(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(state.PeerCertificates[0].Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+	host := state.ServerName
+
+	p.mu.Lock()
+	pinned, ok := p.seen[host]
+	if !ok {
+		p.seen[host] = fingerprint
+	}
+	p.mu.Unlock()
+
+	if !ok || pinned == fingerprint {
+		return nil
+	}
+	if p.policy == PinAbort {
+		return fmt.Errorf("tls: leaf certificate for %s changed mid-scan (pinned %s, got %s)", host, pinned[:16], fingerprint[:16])
+	}
+	log.Printf("tls: leaf certificate for %s changed mid-scan (pinned %s, got %s)", host, pinned[:16], fingerprint[:16])
+	return nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how the client retries failed requests. Transient
+// network errors always qualify; response statuses only qualify when
+// listed. The zero policy disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries per request, including the
+	// first. Values below 2 disable retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; each further retry
+	// doubles it, capped at MaxBackoff.
+	Backoff time.Duration
+	// MaxBackoff caps the exponential growth; zero means 30s.
+	MaxBackoff time.Duration
+	// Jitter randomizes each delay by up to this fraction (0..1) in either
+	// direction, preventing synchronized retry bursts across workers.
+	Jitter float64
+	// Statuses are response codes that trigger a retry (e.g. 429, 503).
+	Statuses []int
+}
+
+// enabled reports whether the policy calls for any retries at all.
+func (p RetryPolicy) enabled() bool { return p.MaxAttempts > 1 }
+
+// retryStatus reports whether a response status qualifies for a retry.
+func (p RetryPolicy) retryStatus(code int) bool {
+	for _, status := range p.Statuses {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
+// delay computes the pause before retry number attempt (1-based).
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	if p.Jitter > 0 {
+		spread := float64(backoff) * p.Jitter
+		backoff += time.Duration((rand.Float64()*2 - 1) * spread)
+		if backoff < 0 {
+			backoff = 0
+		}
+	}
+	return backoff
+}
+
+// doRetry runs the request through the policy. Requests whose bodies
+// cannot be replayed are sent exactly once.
+func (c *Client) doRetry(req *http.Request) (*http.Response, error) {
+	if !c.retry.enabled() || (req.Body != nil && req.GetBody == nil) {
+		return c.doOnce(req)
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq, err = cloneRequest(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp, err = c.doOnce(attemptReq)
+		if err == nil && !c.retry.retryStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt >= c.retry.MaxAttempts {
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(c.retry.delay(attempt)):
+		}
+	}
+}
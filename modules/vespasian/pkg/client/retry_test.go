@@ -0,0 +1,102 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/replay"
+)
+
+// retryClient builds a client over a replay transport with a fast retry
+// policy, so attempts can be counted without touching the network or
+// waiting out real backoff.
+func retryClient(transport *replay.Transport, attempts int) *client.Client {
+	return client.New(client.Options{
+		Transport: transport,
+		Retry: client.RetryPolicy{
+			MaxAttempts: attempts,
+			Backoff:     time.Millisecond,
+			Statuses:    []int{http.StatusServiceUnavailable},
+		},
+	})
+}
+
+// TestRetryRecovers asserts the client keeps retrying a listed status
+// until the target recovers, and that the successful response is the one
+// returned.
+func TestRetryRecovers(t *testing.T) {
+	transport := replay.NewTransport()
+	transport.RecordSequence(http.MethodGet, "http://target.invalid/api",
+		replay.Exchange{StatusCode: http.StatusServiceUnavailable},
+		replay.Exchange{StatusCode: http.StatusServiceUnavailable},
+		replay.Exchange{StatusCode: http.StatusOK, Body: []byte("ok")},
+	)
+
+	resp, err := retryClient(transport, 3).Get(context.Background(), "http://target.invalid/api")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != "ok" {
+		t.Errorf("got status %d body %q, want the recovered 200", resp.StatusCode, resp.Body)
+	}
+	if got := transport.Requests(http.MethodGet, "http://target.invalid/api"); got != 3 {
+		t.Errorf("transport served %d attempts, want 3", got)
+	}
+}
+
+// TestRetryStopsAtMaxAttempts asserts a target that never recovers is
+// given exactly MaxAttempts tries and the final response is surfaced.
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	transport := replay.NewTransport()
+	transport.Record(http.MethodGet, "http://target.invalid/down",
+		replay.Exchange{StatusCode: http.StatusServiceUnavailable})
+
+	resp, err := retryClient(transport, 3).Get(context.Background(), "http://target.invalid/down")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want the final 503", resp.StatusCode)
+	}
+	if got := transport.Requests(http.MethodGet, "http://target.invalid/down"); got != 3 {
+		t.Errorf("transport served %d attempts, want 3", got)
+	}
+}
+
+// TestRetryIgnoresUnlistedStatuses asserts only the configured statuses
+// trigger retries; a plain 500 is returned on the first attempt.
+func TestRetryIgnoresUnlistedStatuses(t *testing.T) {
+	transport := replay.NewTransport()
+	transport.Record(http.MethodGet, "http://target.invalid/error",
+		replay.Exchange{StatusCode: http.StatusInternalServerError})
+
+	resp, err := retryClient(transport, 3).Get(context.Background(), "http://target.invalid/error")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("got status %d, want 500", resp.StatusCode)
+	}
+	if got := transport.Requests(http.MethodGet, "http://target.invalid/error"); got != 1 {
+		t.Errorf("transport served %d attempts, want 1", got)
+	}
+}
+
+// TestRetryDisabledByDefault asserts the zero policy sends each request
+// exactly once even when the response status would otherwise qualify.
+func TestRetryDisabledByDefault(t *testing.T) {
+	transport := replay.NewTransport()
+	transport.Record(http.MethodGet, "http://target.invalid/api",
+		replay.Exchange{StatusCode: http.StatusServiceUnavailable})
+
+	c := client.New(client.Options{Transport: transport})
+	if _, err := c.Get(context.Background(), "http://target.invalid/api"); err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if got := transport.Requests(http.MethodGet, "http://target.invalid/api"); got != 1 {
+		t.Errorf("transport served %d attempts, want 1", got)
+	}
+}
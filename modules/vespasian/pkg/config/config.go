@@ -0,0 +1,717 @@
+// Package config loads scan configuration from a YAML file. Only the small
+// subset of YAML vespasian needs is supported: scalar key/value pairs and
+// comments.
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds scan-wide settings.
+type Config struct {
+	// Profile is the named scan profile last applied ("passive", "safe",
+	// "aggressive"); empty when knobs were set individually.
+	Profile string
+	// Probes restricts the scan to the named probes; empty runs them all.
+	Probes []string
+	// ProbeOptions carries per-probe tuning applied during scan setup.
+	// Each "probe_option:" line holds one "probe key=value" entry; the
+	// named probe must implement probe.Configurable.
+	ProbeOptions map[string]map[string]string
+	// DisabledProbes removes probes from whatever set Probes selects.
+	DisabledProbes []string
+	// Concurrency is the number of probe executions run in parallel.
+	Concurrency int
+	// Timeout bounds each HTTP request.
+	Timeout time.Duration
+	// Insecure disables TLS certificate verification.
+	Insecure bool
+	// MaxBodySize caps buffered response bodies in bytes. Zero uses the
+	// client default; negative disables the cap.
+	MaxBodySize int64
+	// Headers are set on every outbound HTTP request. Each "header:" line
+	// holds one "Name: value" pair.
+	Headers map[string]string
+	// CookieJar keeps session cookies across requests for the life of
+	// the scan instead of treating every request as stateless.
+	CookieJar bool
+	// Cookies pre-seed the jar (implicitly enabling it). Each "cookie:"
+	// line holds one "URL name=value" entry scoped to that URL's host.
+	Cookies []Cookie
+	// LoginURL, when set, receives a login request before probing starts;
+	// cookies it sets persist for the scan (the jar is enabled
+	// implicitly).
+	LoginURL string
+	// LoginMethod is the login request method; defaults to POST.
+	LoginMethod string
+	// LoginBody is the raw login request body, typically JSON credentials.
+	LoginBody string
+	// LoginForm holds login form fields sent URL-encoded instead of
+	// LoginBody, one "login_form: name=value" line each.
+	LoginForm map[string]string
+	// LoginContentType overrides the inferred login content type.
+	LoginContentType string
+	// LoginSuccess is a substring the login response must contain for the
+	// login to count; empty accepts any non-error status.
+	LoginSuccess string
+	// LoginTokenPath is a dotted JSON path ("data.access_token") naming a
+	// bearer token in the login response, attached to every subsequent
+	// request as an Authorization header.
+	LoginTokenPath string
+	// RetryAttempts is the total tries per HTTP request including the
+	// first; values below 2 disable retries.
+	RetryAttempts int
+	// RetryBackoff is the delay before the first retry, doubling each
+	// retry up to RetryMaxBackoff.
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	// RetryJitter randomizes each delay by up to this fraction (0..1).
+	RetryJitter float64
+	// RetryStatuses are response codes that trigger a retry (e.g. 429, 503).
+	RetryStatuses []int
+	// HTTPVersion pins the HTTP protocol version: "auto" (negotiate h2,
+	// fall back to 1.1), "http/1.1", "h2", or "h2c" (prior-knowledge
+	// cleartext HTTP/2). HTTP/3 is not supported: it needs a QUIC stack
+	// vespasian does not vendor.
+	HTTPVersion string
+	// MinConfidence drops endpoints scored below this threshold (0..1).
+	MinConfidence float64
+	// SubdomainEnum expands apex-domain targets into their passively
+	// enumerated subdomains before scanning.
+	SubdomainEnum bool
+	// ArtifactDir, when set, stores retrieved evidence and the scan
+	// manifest under this directory.
+	ArtifactDir string
+	// ArtifactMaxEntries, ArtifactMaxEntrySize, and ArtifactMaxBytes
+	// sample how much evidence is written to disk; zero means unlimited.
+	ArtifactMaxEntries   int
+	ArtifactMaxEntrySize int64
+	ArtifactMaxBytes     int64
+	// Progress renders a live status line on stderr during the scan.
+	Progress bool
+	// Stream emits findings to stdout as NDJSON while the scan runs,
+	// instead of one formatted document at the end. Output, when also
+	// set, still receives the final formatted result.
+	Stream bool
+	// Summaries prints a per-target markdown summary line to stderr as
+	// each target's last probe finishes, so long scans yield partial
+	// deliverables while still running.
+	Summaries bool
+	// ClassifyAuth probes endpoints anonymously after discovery and tags
+	// whether authentication is required.
+	ClassifyAuth bool
+	// VerifyMethods selects the endpoint verification policy: "none",
+	// "safe" (GET/HEAD/OPTIONS only), or "all".
+	VerifyMethods string
+	// DiscoverMethods probes crawler-discovered URLs with OPTIONS plus
+	// lightweight HEAD/POST requests to enumerate supported verbs.
+	DiscoverMethods bool
+	// VersionSweep probes the numerically adjacent versions of every
+	// versioned endpoint (/v1/, /v2/) and reports live neighbors.
+	VersionSweep bool
+	// ServiceDetect runs the pre-flight TLS/HTTP banner check against
+	// each target so probe acceptance follows what the port actually
+	// speaks instead of port lists alone. On by default.
+	ServiceDetect bool
+	// PortScan connect-scans bare-host targets and expands each into one
+	// target per open port before probing.
+	PortScan bool
+	// PortScanPorts overrides the scanned port list; empty uses the
+	// built-in top API ports.
+	PortScanPorts []int
+	// SOAPInvoke sends one empty SOAP envelope to each WSDL-bound
+	// address to confirm liveness and capture fault behavior.
+	SOAPInvoke bool
+	// GraphQLSchemaDir, when set, exports every successfully
+	// introspected GraphQL schema as SDL into this directory.
+	GraphQLSchemaDir string
+	// Enrich annotates endpoints with CDN classification (and ASN data
+	// when EnrichAPI is also set) after discovery.
+	Enrich bool
+	// EnrichAPI is a URL template with "{ip}" pointing at a JSON
+	// IP-intelligence API used for ASN and organization lookups.
+	EnrichAPI string
+	// WebhookURL receives a POSTed scan summary when a scan completes.
+	WebhookURL string
+	// WebhookFormat is "json" (structured summary, default) or "slack"
+	// (Slack-compatible text payload).
+	WebhookFormat string
+	// WebhookOn is "always" (default) or "high" to notify only when
+	// high-confidence endpoints were found.
+	WebhookOn string
+	// PortProfile selects which ports probes consider worth HTTP
+	// probing: "web-default", "api-extended", or "all-ports".
+	PortProfile string
+	// CrawlWorkers is how many crawler fetches run concurrently; zero
+	// uses the crawler default.
+	CrawlWorkers int
+	// CrawlPerHost caps concurrent crawler fetches against one host;
+	// zero uses the crawler default.
+	CrawlPerHost int
+	// CrawlDelay is the minimum gap between crawler fetches to the same
+	// host.
+	CrawlDelay time.Duration
+	// MetricsAddr, when set, serves Prometheus-format scan metrics on
+	// this address ("127.0.0.1:9464") at /metrics for the duration of
+	// the run.
+	MetricsAddr string
+	// OTLPEndpoint, when set, exports a span per probe run (under one
+	// span per scan) to this OpenTelemetry collector base URL
+	// ("http://collector:4318") over OTLP/HTTP at /v1/traces.
+	OTLPEndpoint string
+	// UploadSSE selects S3 server-side encryption ("AES256" or
+	// "aws:kms") for reports written to s3:// output destinations.
+	UploadSSE string
+	// ProtoDir points at a directory of .proto files used to enumerate
+	// gRPC methods on servers without reflection.
+	ProtoDir string
+	// NTLMUser holds "DOMAIN\user" credentials for NTLM/Negotiate
+	// challenges; NTLMPassword is the matching password.
+	NTLMUser     string
+	NTLMPassword string
+	// TLSPinning pins each host's leaf certificate on first contact:
+	// "warn" logs a mid-scan change, "abort" fails the connection, ""
+	// disables pinning.
+	TLSPinning string
+	// ClientCert and ClientKey configure the certificate presented for
+	// mutual TLS. Each is a PEM file path or an inline PEM block; both
+	// must be set together.
+	ClientCert string
+	ClientKey  string
+	// WordlistFile, when set, enables wordlist learning: paths discovered
+	// by this scan are folded into the file, and its highest-ranked paths
+	// feed the learned-paths probe.
+	WordlistFile string
+	// HistoryFile, when set, records endpoint first-seen/last-seen
+	// timestamps across scans; query it with `vespasian history`.
+	HistoryFile string
+	// StoreFile, when set, appends every scan with its targets and
+	// findings to this SQLite database file; query it with `vespasian
+	// query` or any sqlite3 client.
+	StoreFile string
+	// HARFile, when set, records every request/response pair the scan
+	// makes and writes them as a HAR archive at this path. HARMaxBody
+	// truncates recorded bodies; zero keeps har.DefaultMaxBody bytes.
+	HARFile    string
+	HARMaxBody int64
+	// SampleThreshold caps how many endpoints one probe contributes to
+	// the report; the full set overflows into an NDJSON file under
+	// SampleDir. Zero disables sampling.
+	SampleThreshold int
+	SampleDir       string
+	// TraceFile, when set, writes a JSON probe execution trace here.
+	TraceFile string
+	// Dedupe collapses endpoints that differ only in path parameter values
+	// (/users/123, /users/456 → /users/{id}).
+	Dedupe bool
+	// DedupePatterns are extra regexes a path segment may match to count
+	// as a parameter; one "dedupe_pattern:" line each.
+	DedupePatterns []string
+	// TagRules attach tags to matching endpoints. Each "tag:" line holds
+	// a comma-separated tag list followed by a regex matched against the
+	// endpoint URL.
+	TagRules []TagRule
+	// FilterTags keeps only endpoints carrying at least one of these tags.
+	FilterTags []string
+	// PolicyFile points at a severity policy: one rule per line assigning
+	// a severity (and optionally a risk label) to endpoints matching its
+	// clauses, e.g. "high exposed-admin path~^/admin status=200". The
+	// file uses the same line-oriented syntax as this config — not full
+	// YAML or CEL; see pkg/policy for the rule grammar.
+	PolicyFile string
+	// Plugins are external probes run over the exec protocol. Each
+	// "plugin:" line names one: the first word of the value is the probe
+	// name, the rest is the command line.
+	Plugins []Plugin
+	// Dialers route connections to specific hosts through external
+	// commands. Each "dialer:" line names one: the first word is the host
+	// (or host:port), the rest is the command line.
+	Dialers []Dialer
+	// Format selects the output writer (json, json-grouped, terminal,
+	// markdown, sarif, nuclei, burp, cyclonedx, junit).
+	Format string
+	// Output is the output file path; empty means stdout.
+	Output string
+	// Destinations renders the result in several formats at once, one
+	// "destination:" line each as "format" or "format=path". When set,
+	// Format and Output are ignored.
+	Destinations []Destination
+	// Timezone is the IANA zone name ("America/New_York", "Local") all
+	// output timestamps are rendered in; empty means UTC.
+	Timezone string
+	// TimestampFormat renders timestamps as "rfc3339" (default) or
+	// "epoch" (Unix seconds) across writers and the scan manifest.
+	TimestampFormat string
+}
+
+// TagRule attaches tags to endpoints whose URL matches a pattern.
+type TagRule struct {
+	Tags    []string
+	Pattern string
+}
+
+// Plugin names an external probe and the command that runs it.
+type Plugin struct {
+	Name    string
+	Command string
+}
+
+// Dialer routes connections to one host through a command whose stdio
+// becomes the connection, e.g. an SSH tunnel.
+type Dialer struct {
+	Host    string
+	Command string
+}
+
+// Destination pairs an output format with where it is written; an empty
+// Path means stdout.
+type Destination struct {
+	Format string
+	Path   string
+}
+
+// Cookie pre-seeds the scan cookie jar with one session cookie, scoped
+// to the host of URL.
+type Cookie struct {
+	URL   string
+	Name  string
+	Value string
+}
+
+// Default returns the configuration used when no file is supplied.
+func Default() *Config {
+	return &Config{
+		Concurrency:   10,
+		Timeout:       10 * time.Second,
+		Format:        "json",
+		ServiceDetect: true,
+	}
+}
+
+// Load reads a YAML config file, applying defaults for unset keys.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := Default()
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("config: %s:%d: expected key: value", path, i+1)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(strings.SplitN(value, "#", 2)[0])
+		value = strings.Trim(value, `"'`)
+		if err := cfg.set(key, value); err != nil {
+			return nil, fmt.Errorf("config: %s:%d: %w", path, i+1, err)
+		}
+	}
+	return cfg, nil
+}
+
+func (c *Config) set(key, value string) error {
+	switch key {
+	case "profile":
+		return c.ApplyProfile(value)
+	case "probes":
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.Probes = append(c.Probes, name)
+			}
+		}
+	case "probe_option":
+		probeName, pair, ok := strings.Cut(value, " ")
+		optionKey, optionValue, okPair := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !okPair || probeName == "" || optionKey == "" {
+			return fmt.Errorf("invalid probe_option %q, want \"probe key=value\"", value)
+		}
+		if c.ProbeOptions == nil {
+			c.ProbeOptions = map[string]map[string]string{}
+		}
+		if c.ProbeOptions[probeName] == nil {
+			c.ProbeOptions[probeName] = map[string]string{}
+		}
+		c.ProbeOptions[probeName][optionKey] = optionValue
+	case "disabled_probes":
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.DisabledProbes = append(c.DisabledProbes, name)
+			}
+		}
+	case "concurrency":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid concurrency %q", value)
+		}
+		c.Concurrency = n
+	case "timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q", value)
+		}
+		c.Timeout = d
+	case "insecure":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid insecure %q", value)
+		}
+		c.Insecure = b
+	case "header":
+		name, headerValue, ok := strings.Cut(value, ":")
+		name, headerValue = strings.TrimSpace(name), strings.TrimSpace(headerValue)
+		if !ok || name == "" || headerValue == "" {
+			return fmt.Errorf("invalid header %q, want \"Name: value\"", value)
+		}
+		if c.Headers == nil {
+			c.Headers = map[string]string{}
+		}
+		c.Headers[name] = headerValue
+	case "cookie_jar":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid cookie_jar %q", value)
+		}
+		c.CookieJar = b
+	case "cookie":
+		rawURL, pair, ok := strings.Cut(value, " ")
+		name, cookieValue, okPair := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !okPair || rawURL == "" || name == "" {
+			return fmt.Errorf("invalid cookie %q, want \"URL name=value\"", value)
+		}
+		c.Cookies = append(c.Cookies, Cookie{URL: rawURL, Name: name, Value: cookieValue})
+	case "login_url":
+		c.LoginURL = value
+	case "login_method":
+		c.LoginMethod = strings.ToUpper(value)
+	case "login_body":
+		c.LoginBody = value
+	case "login_form":
+		name, fieldValue, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid login_form %q, want \"name=value\"", value)
+		}
+		if c.LoginForm == nil {
+			c.LoginForm = map[string]string{}
+		}
+		c.LoginForm[name] = fieldValue
+	case "login_content_type":
+		c.LoginContentType = value
+	case "login_success":
+		c.LoginSuccess = value
+	case "login_token_path":
+		c.LoginTokenPath = value
+	case "retry_attempts":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid retry_attempts %q", value)
+		}
+		c.RetryAttempts = n
+	case "retry_backoff":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid retry_backoff %q", value)
+		}
+		c.RetryBackoff = d
+	case "retry_max_backoff":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid retry_max_backoff %q", value)
+		}
+		c.RetryMaxBackoff = d
+	case "retry_jitter":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("invalid retry_jitter %q", value)
+		}
+		c.RetryJitter = f
+	case "retry_statuses":
+		for _, field := range strings.Split(value, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || code < 100 || code > 599 {
+				return fmt.Errorf("invalid retry_statuses %q", value)
+			}
+			c.RetryStatuses = append(c.RetryStatuses, code)
+		}
+	case "http_version":
+		switch value {
+		case "auto", "http/1.1", "h2", "h2c":
+			c.HTTPVersion = value
+		case "h3", "http/3":
+			return fmt.Errorf("http_version %q needs a QUIC stack vespasian does not vendor", value)
+		default:
+			return fmt.Errorf("invalid http_version %q", value)
+		}
+	case "min_confidence":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("invalid min_confidence %q", value)
+		}
+		c.MinConfidence = f
+	case "subdomain_enum":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid subdomain_enum %q", value)
+		}
+		c.SubdomainEnum = b
+	case "max_body_size":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid max_body_size %q", value)
+		}
+		c.MaxBodySize = n
+	case "artifact_dir":
+		c.ArtifactDir = value
+	case "artifact_max_entries":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid artifact_max_entries %q", value)
+		}
+		c.ArtifactMaxEntries = n
+	case "artifact_max_entry_size":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid artifact_max_entry_size %q", value)
+		}
+		c.ArtifactMaxEntrySize = n
+	case "artifact_max_bytes":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid artifact_max_bytes %q", value)
+		}
+		c.ArtifactMaxBytes = n
+	case "wordlist_file":
+		c.WordlistFile = value
+	case "history_file":
+		c.HistoryFile = value
+	case "store_file":
+		c.StoreFile = value
+	case "har_file":
+		c.HARFile = value
+	case "har_max_body":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid har_max_body %q", value)
+		}
+		c.HARMaxBody = n
+	case "sample_threshold":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid sample_threshold %q", value)
+		}
+		c.SampleThreshold = n
+	case "sample_dir":
+		c.SampleDir = value
+	case "trace_file":
+		c.TraceFile = value
+	case "progress":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid progress %q", value)
+		}
+		c.Progress = b
+	case "stream":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid stream %q", value)
+		}
+		c.Stream = b
+	case "summaries":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid summaries %q", value)
+		}
+		c.Summaries = b
+	case "classify_auth":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid classify_auth %q", value)
+		}
+		c.ClassifyAuth = b
+	case "verify_methods":
+		c.VerifyMethods = value
+	case "discover_methods":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid discover_methods %q", value)
+		}
+		c.DiscoverMethods = b
+	case "version_sweep":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid version_sweep %q", value)
+		}
+		c.VersionSweep = b
+	case "service_detect":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid service_detect %q", value)
+		}
+		c.ServiceDetect = b
+	case "port_scan":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid port_scan %q", value)
+		}
+		c.PortScan = b
+	case "port_scan_ports":
+		for _, field := range strings.Split(value, ",") {
+			port, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("invalid port_scan_ports %q", value)
+			}
+			c.PortScanPorts = append(c.PortScanPorts, port)
+		}
+	case "soap_invoke":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid soap_invoke %q", value)
+		}
+		c.SOAPInvoke = b
+	case "graphql_schema_dir":
+		c.GraphQLSchemaDir = value
+	case "enrich":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid enrich %q", value)
+		}
+		c.Enrich = b
+	case "enrich_api":
+		c.EnrichAPI = value
+	case "port_profile":
+		c.PortProfile = value
+	case "crawl_workers":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid crawl_workers %q", value)
+		}
+		c.CrawlWorkers = n
+	case "crawl_per_host":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid crawl_per_host %q", value)
+		}
+		c.CrawlPerHost = n
+	case "crawl_delay":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid crawl_delay %q", value)
+		}
+		c.CrawlDelay = d
+	case "metrics_addr":
+		c.MetricsAddr = value
+	case "otlp_endpoint":
+		c.OTLPEndpoint = value
+	case "upload_sse":
+		if value != "AES256" && value != "aws:kms" {
+			return fmt.Errorf("invalid upload_sse %q, want AES256 or aws:kms", value)
+		}
+		c.UploadSSE = value
+	case "webhook_url":
+		c.WebhookURL = value
+	case "webhook_format":
+		switch value {
+		case "json", "slack":
+			c.WebhookFormat = value
+		default:
+			return fmt.Errorf("invalid webhook_format %q", value)
+		}
+	case "webhook_on":
+		switch value {
+		case "always", "high":
+			c.WebhookOn = value
+		default:
+			return fmt.Errorf("invalid webhook_on %q", value)
+		}
+	case "proto_dir":
+		c.ProtoDir = value
+	case "ntlm_user":
+		c.NTLMUser = value
+	case "ntlm_password":
+		c.NTLMPassword = value
+	case "tls_pinning":
+		if value != "warn" && value != "abort" {
+			return fmt.Errorf("invalid tls_pinning %q (want warn or abort)", value)
+		}
+		c.TLSPinning = value
+	case "client_cert":
+		c.ClientCert = value
+	case "client_key":
+		c.ClientKey = value
+	case "dedupe":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid dedupe %q", value)
+		}
+		c.Dedupe = b
+	case "dedupe_pattern":
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid dedupe_pattern %q: %v", value, err)
+		}
+		c.DedupePatterns = append(c.DedupePatterns, value)
+	case "tag":
+		tags, pattern, ok := strings.Cut(value, " ")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return fmt.Errorf("invalid tag %q, want \"tag1,tag2 pattern\"", value)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid tag pattern %q: %v", pattern, err)
+		}
+		c.TagRules = append(c.TagRules, TagRule{Tags: strings.Split(tags, ","), Pattern: pattern})
+	case "filter_tags":
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				c.FilterTags = append(c.FilterTags, tag)
+			}
+		}
+	case "policy_file":
+		c.PolicyFile = value
+	case "plugin":
+		name, command, ok := strings.Cut(value, " ")
+		if !ok || strings.TrimSpace(command) == "" {
+			return fmt.Errorf("invalid plugin %q, want \"name command...\"", value)
+		}
+		c.Plugins = append(c.Plugins, Plugin{Name: name, Command: strings.TrimSpace(command)})
+	case "dialer":
+		host, command, ok := strings.Cut(value, " ")
+		if !ok || strings.TrimSpace(command) == "" {
+			return fmt.Errorf("invalid dialer %q, want \"host command...\"", value)
+		}
+		c.Dialers = append(c.Dialers, Dialer{Host: host, Command: strings.TrimSpace(command)})
+	case "format":
+		c.Format = value
+	case "output":
+		c.Output = value
+	case "destination":
+		format, path, _ := strings.Cut(value, "=")
+		if format == "" {
+			return fmt.Errorf("invalid destination %q, want \"format\" or \"format=path\"", value)
+		}
+		c.Destinations = append(c.Destinations, Destination{Format: format, Path: path})
+	case "timezone":
+		if _, err := time.LoadLocation(value); err != nil {
+			return fmt.Errorf("invalid timezone %q", value)
+		}
+		c.Timezone = value
+	case "timestamp_format":
+		switch value {
+		case "rfc3339", "epoch":
+			c.TimestampFormat = value
+		default:
+			return fmt.Errorf("invalid timestamp_format %q", value)
+		}
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	return nil
+}
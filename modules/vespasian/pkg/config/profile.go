@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ApplyProfile overwrites the scan knobs a named profile bundles, so
+// operators pick one word instead of hand-tuning a dozen settings. The
+// profile wins over individual keys; set Profile last (or not at all) to
+// keep manual control.
+//
+//	passive    no requests beyond third-party archives; nothing sent to
+//	           the target itself is verified or classified
+//	safe       the full probe set minus wordlist-driven guessing, safe
+//	           verification methods only
+//	aggressive everything on: learned wordlists, subdomain expansion,
+//	           unrestricted verification, higher concurrency
+func (c *Config) ApplyProfile(name string) error {
+	switch name {
+	case "":
+		return nil
+	case "passive":
+		c.Probes = []string{"historical"}
+		c.VerifyMethods = "none"
+		c.ClassifyAuth = false
+		c.SubdomainEnum = false
+		c.ServiceDetect = false
+	case "safe":
+		c.Probes = nil // all registered probes
+		c.DisabledProbes = []string{"learned-paths"}
+		c.VerifyMethods = "safe"
+		c.Concurrency = 10
+	case "aggressive":
+		c.Probes = nil
+		c.DisabledProbes = nil
+		c.VerifyMethods = "all"
+		c.ClassifyAuth = true
+		c.DiscoverMethods = true
+		c.VersionSweep = true
+		c.SubdomainEnum = true
+		c.Concurrency = 20
+		c.Timeout = 15 * time.Second
+	default:
+		return fmt.Errorf("unknown profile %q (want passive, safe, or aggressive)", name)
+	}
+	c.Profile = name
+	return nil
+}
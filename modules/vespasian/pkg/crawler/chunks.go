@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/js"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Lazy chunks are where SPAs hide most of their routes and API calls:
+// the crawler only ever sees the entry bundle, and everything behind an
+// import() lands in chunk files no link points to. The chunk pass
+// reconstructs their URLs from the webpack runtime's filename builder
+// and from Vite build manifests, downloads them in scope, and mines each
+// one like any other bundle.
+
+// webpackChunkMapRe matches the webpack chunk filename builder: a path
+// prefix, the chunk id, and an id-to-hash map feeding a suffix, e.g.
+// "static/js/" + e + "." + {179:"ab12cd"}[e] + ".chunk.js".
+var webpackChunkMapRe = regexp.MustCompile(
+	`"([^"{}+]*)"\s*\+\s*[\w$]+\s*\+\s*"\."\s*\+\s*(\{[^{}]*\})\s*\[[\w$]+\]\s*\+\s*"([^"]*\.m?js)"`)
+
+// chunkPairRe pulls the id: "hash" pairs out of the builder's map.
+var chunkPairRe = regexp.MustCompile(`"?([\w-]+)"?\s*:\s*"(\w+)"`)
+
+// maxChunks caps how many reconstructed chunk URLs one crawl downloads.
+const maxChunks = 24
+
+// viteManifestPaths are where Vite and its framework integrations
+// deploy the build manifest.
+var viteManifestPaths = []string{
+	"/.vite/manifest.json",
+	"/manifest.json",
+	"/assets/manifest.json",
+	"/build/manifest.json",
+}
+
+// chunkEndpoints enumerates lazily-loaded chunks, fetches them, and
+// mines each for endpoints the entry bundles never mention.
+func (cr *Crawler) chunkEndpoints(ctx context.Context, base *url.URL, bundles []bundle) []types.Endpoint {
+	have := map[string]bool{}
+	for _, b := range bundles {
+		have[b.url] = true
+	}
+
+	// chunk URL -> where its existence was learned, insertion-ordered.
+	origins := map[string]string{}
+	var order []string
+	add := func(chunkURL, origin string) {
+		if chunkURL == "" || have[chunkURL] || origins[chunkURL] != "" {
+			return
+		}
+		origins[chunkURL] = origin
+		order = append(order, chunkURL)
+	}
+
+	for _, b := range bundles {
+		for _, path := range webpackChunkPaths(b.body) {
+			if resolved, ok := resolveSameHost(base, b.url, path); ok {
+				add(resolved, b.url)
+			}
+		}
+	}
+	for manifestURL, files := range cr.viteManifests(ctx, base) {
+		for _, file := range files {
+			if resolved, ok := resolveSameHost(base, base.String()+"/", file); ok {
+				add(resolved, manifestURL)
+			}
+		}
+	}
+
+	var endpoints []types.Endpoint
+	fetched := 0
+	for _, chunkURL := range order {
+		if fetched >= maxChunks {
+			break
+		}
+		resp, err := cr.client.Get(ctx, chunkURL)
+		if err != nil || resp.StatusCode != 200 ||
+			!strings.Contains(resp.Header.Get("Content-Type"), "javascript") {
+			continue
+		}
+		fetched++
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        chunkURL,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "crawler",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   map[string]string{"origin": origins[chunkURL], "kind": "chunk"},
+		})
+		for _, call := range js.Extract(resp.Body) {
+			resolved, ok := resolveSameHost(base, chunkURL, call.URL)
+			if !ok {
+				continue
+			}
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        resolved,
+				Method:     call.Method,
+				Protocol:   types.ProtocolHTTP,
+				Source:     "crawler",
+				Confidence: types.ConfidenceMedium,
+				Metadata:   map[string]string{"origin": chunkURL, "kind": call.Kind},
+			})
+		}
+	}
+	return endpoints
+}
+
+// webpackChunkPaths reconstructs chunk file paths from a bundle's
+// webpack runtime, when it contains one.
+func webpackChunkPaths(body []byte) []string {
+	if !bytes.Contains(body, []byte("__webpack_require__")) &&
+		!bytes.Contains(body, []byte("webpackChunk")) &&
+		!bytes.Contains(body, []byte("webpackJsonp")) {
+		return nil
+	}
+	var paths []string
+	for _, match := range webpackChunkMapRe.FindAllSubmatch(body, -1) {
+		prefix, pairs, suffix := string(match[1]), match[2], string(match[3])
+		for _, pair := range chunkPairRe.FindAllSubmatch(pairs, -1) {
+			paths = append(paths, prefix+string(pair[1])+"."+string(pair[2])+suffix)
+		}
+	}
+	return paths
+}
+
+// viteManifests fetches the conventional Vite manifest locations and
+// returns the script files each one lists, keyed by manifest URL. A web
+// app manifest at the same path parses but lists no files, so it falls
+// out naturally.
+func (cr *Crawler) viteManifests(ctx context.Context, base *url.URL) map[string][]string {
+	manifests := map[string][]string{}
+	for _, path := range viteManifestPaths {
+		manifestURL := base.String() + path
+		resp, err := cr.client.Get(ctx, manifestURL)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		var entries map[string]struct {
+			File           string   `json:"file"`
+			Imports        []string `json:"imports"`
+			DynamicImports []string `json:"dynamicImports"`
+		}
+		if err := json.Unmarshal(resp.Body, &entries); err != nil {
+			continue
+		}
+		var files []string
+		for _, entry := range entries {
+			file := entry.File
+			if file == "" || !strings.HasSuffix(file, ".js") && !strings.HasSuffix(file, ".mjs") {
+				continue
+			}
+			files = append(files, file)
+		}
+		if len(files) > 0 {
+			manifests[manifestURL] = files
+			break
+		}
+	}
+	return manifests
+}
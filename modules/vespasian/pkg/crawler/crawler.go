@@ -0,0 +1,392 @@
+// Package crawler walks a target's HTML surface to discover endpoints the
+// spec-driven probes miss. The crawl queue is seeded from the landing page
+// plus robots.txt and sitemap.xml, which frequently reveal paths no link
+// points to.
+package crawler
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/js"
+	"github.com/praetorian-inc/vespasian/pkg/metrics"
+	"github.com/praetorian-inc/vespasian/pkg/secrets"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Options configures a crawl.
+type Options struct {
+	// MaxPages bounds how many URLs are fetched; defaults to 50.
+	MaxPages int
+	// MaxDepth bounds link-following depth from the seeds; defaults to 3.
+	MaxDepth int
+	// MaxRetries is how many times a failed fetch is retried before the
+	// URL is given up on; defaults to 1.
+	MaxRetries int
+	// ErrorBudget aborts the crawl after this many URLs fail all their
+	// retries, so a dead or aggressively blocking host does not burn the
+	// whole page budget. Defaults to 20.
+	ErrorBudget int
+	// Workers is how many fetches run concurrently; defaults to 4.
+	Workers int
+	// PerHost caps concurrent fetches against one host; defaults to 2.
+	PerHost int
+	// Delay is the minimum gap between fetches to the same host; zero
+	// sends requests as fast as the per-host cap allows.
+	Delay time.Duration
+}
+
+// Crawler fetches pages and follows same-host links.
+type Crawler struct {
+	client *client.Client
+	opts   Options
+}
+
+// New returns a Crawler using c for all fetches.
+func New(c *client.Client, opts Options) *Crawler {
+	if opts.MaxPages < 1 {
+		opts.MaxPages = 50
+	}
+	if opts.MaxDepth < 1 {
+		opts.MaxDepth = 3
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = 1
+	}
+	if opts.ErrorBudget < 1 {
+		opts.ErrorBudget = 20
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 4
+	}
+	if opts.PerHost < 1 {
+		opts.PerHost = 2
+	}
+	return &Crawler{client: c, opts: opts}
+}
+
+// frontierSize tracks how many URLs are waiting to be fetched, so an
+// operator can see whether a crawl is winding down or still expanding.
+var frontierSize = metrics.NewGauge("vespasian_crawl_frontier_size",
+	"URLs queued in the crawl frontier.")
+
+// linkRe pulls href/src attribute values out of HTML.
+var linkRe = regexp.MustCompile(`(?i)(?:href|src|action)\s*=\s*["']([^"'#]+)["']`)
+
+type queueItem struct {
+	url   string
+	depth int
+}
+
+// Crawl walks base and returns an endpoint for every same-host URL that
+// answered. base is a URL like "https://host:port" with no trailing slash.
+func (cr *Crawler) Crawl(ctx context.Context, base string) ([]types.Endpoint, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := []queueItem{{url: base + "/", depth: 0}}
+	for _, seed := range cr.seedURLs(ctx, base) {
+		queue = append(queue, queueItem{url: seed, depth: 0})
+	}
+
+	// Workers pull from the shared frontier under one lock; a worker
+	// sleeps while the frontier is empty but fetches are still in flight,
+	// since those fetches may refill it.
+	var (
+		mu           sync.Mutex
+		cond         = sync.NewCond(&mu)
+		visited      = map[string]bool{}
+		mined        = map[string]bool{}
+		endpoints    []types.Endpoint
+		htmlPages    [][]byte
+		bundles      []bundle
+		fetched      int
+		failed       int
+		inflight     int
+		stopped      bool
+		budgetLogged bool
+	)
+	limiter := newPoliteness(cr.opts.PerHost, cr.opts.Delay)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cr.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				for !stopped && len(queue) == 0 && inflight > 0 {
+					cond.Wait()
+				}
+				if ctx.Err() != nil || fetched >= cr.opts.MaxPages || (len(queue) == 0 && inflight == 0) {
+					stopped = true
+				}
+				if failed >= cr.opts.ErrorBudget {
+					if !budgetLogged {
+						budgetLogged = true
+						log.Printf("crawler: error budget of %d exhausted on %s, stopping", cr.opts.ErrorBudget, base)
+					}
+					stopped = true
+				}
+				if stopped {
+					cond.Broadcast()
+					mu.Unlock()
+					return
+				}
+				item := queue[0]
+				queue = queue[1:]
+				frontierSize.Set(float64(len(queue)))
+				if visited[item.url] {
+					mu.Unlock()
+					continue
+				}
+				visited[item.url] = true
+				// Reserve the page slot before fetching so concurrent
+				// workers cannot overshoot MaxPages together.
+				fetched++
+				inflight++
+				mu.Unlock()
+
+				host := hostOf(item.url)
+				limiter.acquire(host)
+				resp, err := cr.fetch(ctx, item.url)
+				limiter.release(host)
+
+				mu.Lock()
+				inflight--
+				if err != nil {
+					fetched--
+					failed++
+					cond.Broadcast()
+					mu.Unlock()
+					continue
+				}
+				if resp.StatusCode >= 400 {
+					cond.Broadcast()
+					mu.Unlock()
+					continue
+				}
+
+				contentType := resp.Header.Get("Content-Type")
+				metadata := map[string]string{"status": strconv.Itoa(resp.StatusCode)}
+				if scannable(contentType) {
+					for _, match := range secrets.Scan(resp.Body) {
+						metadata["secret."+match.Rule] = match.Redacted
+					}
+				}
+				endpoints = append(endpoints, types.Endpoint{
+					URL:        item.url,
+					Method:     "GET",
+					Protocol:   types.ProtocolHTTP,
+					Source:     "crawler",
+					Confidence: types.ConfidenceMedium,
+					Metadata:   metadata,
+				})
+
+				// Script bundles carry the API surface SPAs never link
+				// to; mine them for request sites and router paths.
+				if strings.Contains(contentType, "javascript") {
+					bundles = append(bundles, bundle{url: item.url, body: resp.Body})
+					for _, call := range js.Extract(resp.Body) {
+						resolved, ok := resolveSameHost(baseURL, item.url, call.URL)
+						if !ok || mined[resolved+" "+call.Method] {
+							continue
+						}
+						mined[resolved+" "+call.Method] = true
+						endpoints = append(endpoints, types.Endpoint{
+							URL:        resolved,
+							Method:     call.Method,
+							Protocol:   types.ProtocolHTTP,
+							Source:     "crawler",
+							Confidence: types.ConfidenceMedium,
+							Metadata: map[string]string{
+								"origin": item.url,
+								"kind":   call.Kind,
+							},
+						})
+					}
+				}
+
+				if item.depth < cr.opts.MaxDepth && strings.Contains(contentType, "text/html") {
+					htmlPages = append(htmlPages, resp.Body)
+					for _, link := range extractLinks(resp.Body) {
+						resolved, ok := resolveSameHost(baseURL, item.url, link)
+						if !ok || visited[resolved] {
+							continue
+						}
+						queue = append(queue, queueItem{url: resolved, depth: item.depth + 1})
+					}
+					frontierSize.Set(float64(len(queue)))
+				}
+				cond.Broadcast()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return endpoints, err
+	}
+
+	// PWAs declare surface in their manifest and service workers that no
+	// link points at; fold it in, skipping URLs the crawl already covered.
+	for _, endpoint := range cr.pwaEndpoints(ctx, base, htmlPages) {
+		if !visited[endpoint.URL] {
+			visited[endpoint.URL] = true
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	// Original sources behind minified bundles name endpoints the
+	// minified code hides; skip what bundle mining already found. The
+	// chunk pass then pulls in the lazily-loaded bundles no link
+	// reaches and mines those too.
+	for _, endpoint := range cr.sourceMapEndpoints(ctx, baseURL, bundles) {
+		key := endpoint.URL + " " + endpoint.Method
+		if mined[key] || visited[endpoint.URL] {
+			continue
+		}
+		mined[key] = true
+		endpoints = append(endpoints, endpoint)
+	}
+	for _, endpoint := range cr.chunkEndpoints(ctx, baseURL, bundles) {
+		key := endpoint.URL + " " + endpoint.Method
+		if mined[key] || visited[endpoint.URL] {
+			continue
+		}
+		mined[key] = true
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// politeness enforces per-host concurrency and request spacing so a
+// parallel crawl does not hammer one host.
+type politeness struct {
+	perHost int
+	delay   time.Duration
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+	last  map[string]time.Time
+}
+
+func newPoliteness(perHost int, delay time.Duration) *politeness {
+	return &politeness{
+		perHost: perHost,
+		delay:   delay,
+		slots:   map[string]chan struct{}{},
+		last:    map[string]time.Time{},
+	}
+}
+
+func (p *politeness) acquire(host string) {
+	p.mu.Lock()
+	slot, ok := p.slots[host]
+	if !ok {
+		slot = make(chan struct{}, p.perHost)
+		p.slots[host] = slot
+	}
+	p.mu.Unlock()
+	slot <- struct{}{}
+	if p.delay <= 0 {
+		return
+	}
+	for {
+		p.mu.Lock()
+		wait := p.delay - time.Since(p.last[host])
+		if wait <= 0 {
+			p.last[host] = time.Now()
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+func (p *politeness) release(host string) {
+	p.mu.Lock()
+	slot := p.slots[host]
+	p.mu.Unlock()
+	<-slot
+}
+
+// hostOf returns the host:port a URL addresses, for politeness keying.
+func hostOf(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return parsed.Host
+}
+
+// scannable reports whether a content type is worth scanning for leaked
+// secrets: HTML pages, scripts, and JSON config blobs.
+func scannable(contentType string) bool {
+	for _, t := range []string{"text/html", "javascript", "json", "text/plain"} {
+		if strings.Contains(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch retrieves a URL, retrying transient failures up to MaxRetries.
+func (cr *Crawler) fetch(ctx context.Context, url string) (*client.Response, error) {
+	var resp *client.Response
+	var err error
+	for attempt := 0; attempt <= cr.opts.MaxRetries; attempt++ {
+		resp, err = cr.client.Get(ctx, url)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// extractLinks returns raw link targets found in an HTML document.
+func extractLinks(body []byte) []string {
+	matches := linkRe.FindAllSubmatch(body, -1)
+	links := make([]string, 0, len(matches))
+	for _, m := range matches {
+		links = append(links, string(m[1]))
+	}
+	return links
+}
+
+// resolveSameHost resolves link against the page it appeared on and
+// reports whether the result stays on the crawl host.
+func resolveSameHost(base *url.URL, page, link string) (string, bool) {
+	if strings.HasPrefix(link, "javascript:") || strings.HasPrefix(link, "mailto:") ||
+		strings.HasPrefix(link, "data:") {
+		return "", false
+	}
+	pageURL, err := url.Parse(page)
+	if err != nil {
+		return "", false
+	}
+	resolved, err := pageURL.Parse(link)
+	if err != nil {
+		return "", false
+	}
+	if resolved.Host != base.Host {
+		return "", false
+	}
+	resolved.Fragment = ""
+	return resolved.String(), true
+}
@@ -0,0 +1,109 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// swRegisterRe finds service worker registrations in page scripts.
+var swRegisterRe = regexp.MustCompile(`serviceWorker\s*\.\s*register\s*\(\s*["']([^"']+)["']`)
+
+// swURLRe matches the URL-shaped strings service worker scripts cache and
+// fetch: absolute URLs and rooted paths.
+var swURLRe = regexp.MustCompile(`["'](https?://[^"'\s]+|/[A-Za-z0-9_./{}-]+)["']`)
+
+// webManifest is the subset of a web app manifest naming URLs.
+type webManifest struct {
+	StartURL  string `json:"start_url"`
+	Scope     string `json:"scope"`
+	Shortcuts []struct {
+		URL string `json:"url"`
+	} `json:"shortcuts"`
+	ShareTarget struct {
+		Action string `json:"action"`
+	} `json:"share_target"`
+}
+
+// pwaEndpoints collects the API surface PWAs declare outside their pages:
+// the web app manifest's URLs and the routes service worker scripts cache.
+// Link crawling misses all of it.
+func (cr *Crawler) pwaEndpoints(ctx context.Context, base string, pages [][]byte) []types.Endpoint {
+	var endpoints []types.Endpoint
+	add := func(url, origin string, confidence float64) {
+		if strings.HasPrefix(url, "/") {
+			url = base + url
+		}
+		if !strings.HasPrefix(url, base) {
+			return
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        url,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "crawler",
+			Confidence: confidence,
+			Metadata:   map[string]string{"origin": origin},
+		})
+	}
+
+	for _, path := range []string{"/manifest.json", "/manifest.webmanifest", "/site.webmanifest"} {
+		resp, err := cr.client.Get(ctx, base+path)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		var manifest webManifest
+		if err := json.Unmarshal(resp.Body, &manifest); err != nil {
+			continue
+		}
+		origin := base + path
+		if manifest.StartURL != "" {
+			add(manifest.StartURL, origin, types.ConfidenceMedium)
+		}
+		if manifest.Scope != "" && manifest.Scope != "/" {
+			add(manifest.Scope, origin, types.ConfidenceLow)
+		}
+		for _, shortcut := range manifest.Shortcuts {
+			if shortcut.URL != "" {
+				add(shortcut.URL, origin, types.ConfidenceMedium)
+			}
+		}
+		if manifest.ShareTarget.Action != "" {
+			add(manifest.ShareTarget.Action, origin, types.ConfidenceMedium)
+		}
+		break
+	}
+
+	// Service worker scripts: registrations found in crawled pages plus
+	// the conventional default locations.
+	scripts := map[string]bool{"/sw.js": true, "/service-worker.js": true}
+	for _, page := range pages {
+		for _, match := range swRegisterRe.FindAllSubmatch(page, -1) {
+			script := string(match[1])
+			if strings.HasPrefix(script, "/") {
+				scripts[script] = true
+			}
+		}
+	}
+	for script := range scripts {
+		resp, err := cr.client.Get(ctx, base+script)
+		if err != nil || resp.StatusCode != 200 ||
+			!strings.Contains(resp.Header.Get("Content-Type"), "javascript") {
+			continue
+		}
+		for _, match := range swURLRe.FindAllSubmatch(resp.Body, -1) {
+			cached := string(match[1])
+			// Static assets the worker precaches are noise; keep the
+			// API-looking routes.
+			if !strings.Contains(cached, "/api") && !strings.Contains(cached, "/graphql") &&
+				!strings.Contains(cached, "/push") && !strings.Contains(cached, "/v1") {
+				continue
+			}
+			add(cached, base+script, types.ConfidenceLow)
+		}
+	}
+	return endpoints
+}
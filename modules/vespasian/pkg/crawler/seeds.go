@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+)
+
+// seedURLs collects crawl seeds from robots.txt and sitemap.xml. Disallow
+// rules are deliberately treated as discovery hints — vespasian is an
+// assessment tool, and operators usually care most about the paths a site
+// asks crawlers to skip.
+func (cr *Crawler) seedURLs(ctx context.Context, base string) []string {
+	var seeds []string
+
+	sitemaps := []string{base + "/sitemap.xml"}
+	if resp, err := cr.client.Get(ctx, base+"/robots.txt"); err == nil && resp.StatusCode == 200 {
+		paths, extra := parseRobots(resp.Body)
+		for _, path := range paths {
+			seeds = append(seeds, base+path)
+		}
+		sitemaps = append(sitemaps, extra...)
+	}
+
+	for _, sitemap := range sitemaps {
+		resp, err := cr.client.Get(ctx, sitemap)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		locations, nested := parseSitemap(resp.Body)
+		seeds = append(seeds, locations...)
+		// Follow one level of sitemap index nesting.
+		for _, child := range nested {
+			childResp, err := cr.client.Get(ctx, child)
+			if err != nil || childResp.StatusCode != 200 {
+				continue
+			}
+			childLocations, _ := parseSitemap(childResp.Body)
+			seeds = append(seeds, childLocations...)
+		}
+	}
+	return seeds
+}
+
+// parseRobots extracts Allow/Disallow paths and Sitemap URLs from a
+// robots.txt body. Wildcard rules are trimmed to their literal prefix.
+func parseRobots(body []byte) (paths, sitemaps []string) {
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "allow", "disallow":
+			if i := strings.IndexAny(value, "*$"); i >= 0 {
+				value = value[:i]
+			}
+			if strings.HasPrefix(value, "/") && value != "/" {
+				paths = append(paths, value)
+			}
+		case "sitemap":
+			if value != "" {
+				sitemaps = append(sitemaps, value)
+			}
+		}
+	}
+	return paths, sitemaps
+}
+
+// sitemapDoc covers both <urlset> and <sitemapindex> documents; only the
+// <loc> values matter here.
+type sitemapDoc struct {
+	XMLName  xml.Name     `xml:""`
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// parseSitemap returns page locations and, for sitemap indexes, the nested
+// sitemap URLs.
+func parseSitemap(body []byte) (locations, nested []string) {
+	var doc sitemapDoc
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, nil
+	}
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			locations = append(locations, loc)
+		}
+	}
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			nested = append(nested, loc)
+		}
+	}
+	return locations, nested
+}
@@ -0,0 +1,152 @@
+package crawler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/js"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// sourceMappingRe finds the sourceMappingURL pragma minifiers append to
+// bundles.
+var sourceMappingRe = regexp.MustCompile(`//[#@]\s*sourceMappingURL=(\S+)`)
+
+// maxSourceMaps caps how many bundle maps one crawl retrieves; SPAs can
+// ship dozens of chunks and the first few carry most of the surface.
+const maxSourceMaps = 8
+
+// bundle is a fetched script kept for the source map pass.
+type bundle struct {
+	url  string
+	body []byte
+}
+
+// sourceMap is the subset of the source map format the miner reads.
+// sourcesContent, when the build embeds it, is the original pre-minified
+// source per entry in sources.
+type sourceMap struct {
+	Version        int      `json:"version"`
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent"`
+}
+
+// sourceMapEndpoints recovers endpoints from the original sources behind
+// minified bundles. Minification defeats constant folding and mangles
+// call shapes; the .map file, when deployed alongside the bundle, undoes
+// all of it. A publicly fetchable map is also reported as a finding in
+// its own right, since it hands out the application's source code.
+func (cr *Crawler) sourceMapEndpoints(ctx context.Context, base *url.URL, bundles []bundle) []types.Endpoint {
+	var endpoints []types.Endpoint
+	fetched := 0
+	for _, b := range bundles {
+		if fetched >= maxSourceMaps {
+			break
+		}
+		mapURL, doc, exposed := cr.resolveSourceMap(ctx, b)
+		if doc == nil {
+			continue
+		}
+		fetched++
+
+		if exposed {
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        mapURL,
+				Method:     "GET",
+				Protocol:   types.ProtocolHTTP,
+				Source:     "crawler",
+				Confidence: types.ConfidenceHigh,
+				Metadata: map[string]string{
+					"risk":    "exposed-source-map",
+					"bundle":  b.url,
+					"sources": strconv.Itoa(len(doc.Sources)),
+				},
+			})
+		}
+
+		for i, content := range doc.SourcesContent {
+			for _, call := range js.Extract([]byte(content)) {
+				resolved, ok := resolveSameHost(base, b.url, call.URL)
+				if !ok {
+					continue
+				}
+				metadata := map[string]string{
+					"origin": mapURL,
+					"kind":   call.Kind,
+				}
+				if i < len(doc.Sources) {
+					metadata["original_source"] = doc.Sources[i]
+				}
+				endpoints = append(endpoints, types.Endpoint{
+					URL:        resolved,
+					Method:     call.Method,
+					Protocol:   types.ProtocolHTTP,
+					Source:     "crawler",
+					Confidence: types.ConfidenceMedium,
+					Metadata:   metadata,
+				})
+			}
+		}
+	}
+	return endpoints
+}
+
+// resolveSourceMap locates and parses the map for a bundle: the
+// sourceMappingURL pragma (including inline data: maps) first, then the
+// bundle.js.map convention. exposed reports whether the map came over
+// the network rather than inlined in the bundle itself.
+func (cr *Crawler) resolveSourceMap(ctx context.Context, b bundle) (string, *sourceMap, bool) {
+	if match := sourceMappingRe.FindSubmatch(b.body); match != nil {
+		ref := string(match[1])
+		if strings.HasPrefix(ref, "data:") {
+			return b.url, parseInlineSourceMap(ref), false
+		}
+		bundleURL, err := url.Parse(b.url)
+		if err == nil {
+			if resolved, err := bundleURL.Parse(ref); err == nil {
+				if doc := cr.fetchSourceMap(ctx, resolved.String()); doc != nil {
+					return resolved.String(), doc, true
+				}
+			}
+		}
+	}
+	mapURL := b.url + ".map"
+	if doc := cr.fetchSourceMap(ctx, mapURL); doc != nil {
+		return mapURL, doc, true
+	}
+	return "", nil, false
+}
+
+func (cr *Crawler) fetchSourceMap(ctx context.Context, url string) *sourceMap {
+	resp, err := cr.client.Get(ctx, url)
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	return parseSourceMap(resp.Body)
+}
+
+// parseInlineSourceMap decodes a data:application/json;base64 pragma.
+func parseInlineSourceMap(ref string) *sourceMap {
+	i := strings.Index(ref, "base64,")
+	if i < 0 {
+		return nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(ref[i+len("base64,"):])
+	if err != nil {
+		return nil
+	}
+	return parseSourceMap(decoded)
+}
+
+func parseSourceMap(body []byte) *sourceMap {
+	var doc sourceMap
+	if err := json.Unmarshal(body, &doc); err != nil || doc.Version == 0 || len(doc.Sources) == 0 {
+		return nil
+	}
+	return &doc
+}
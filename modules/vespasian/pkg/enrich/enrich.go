@@ -0,0 +1,147 @@
+// Package enrich annotates findings with network context: the ASN and
+// organization behind a host's IP and whether the host is fronted by a
+// known CDN. CDN-fronted endpoints and origin servers look the same in a
+// bare inventory but call for different testing, so the distinction is
+// worth carrying in metadata.
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+// Info is the enrichment recorded for one host.
+type Info struct {
+	ASN string
+	Org string
+	CDN string
+}
+
+// cdnSuffixes classify a host as CDN-fronted from its CNAME chain; the
+// suffixes are stable provider domains, not edge hostnames.
+var cdnSuffixes = map[string]string{
+	".cloudfront.net":     "cloudfront",
+	".akamaiedge.net":     "akamai",
+	".akamaized.net":      "akamai",
+	".edgekey.net":        "akamai",
+	".edgesuite.net":      "akamai",
+	".fastly.net":         "fastly",
+	".fastlylb.net":       "fastly",
+	".cdn.cloudflare.net": "cloudflare",
+	".azureedge.net":      "azure-cdn",
+	".azurefd.net":        "azure-frontdoor",
+	".edgecastcdn.net":    "edgecast",
+	".llnwd.net":          "limelight",
+	".cachefly.net":       "cachefly",
+	".b-cdn.net":          "bunny",
+}
+
+// Enricher looks up network context per host and caches it for the life
+// of the scan. The zero APIURL disables ASN lookups; CDN classification
+// needs only DNS.
+type Enricher struct {
+	// APIURL is a URL template with "{ip}" standing in for the address,
+	// pointing at a JSON IP-intelligence API the engagement has approved.
+	APIURL string
+
+	mu    sync.Mutex
+	cache map[string]Info
+}
+
+// New returns an Enricher querying apiURL ("" disables ASN lookups).
+func New(apiURL string) *Enricher {
+	return &Enricher{APIURL: apiURL, cache: map[string]Info{}}
+}
+
+// Enrich returns the network context for host, consulting DNS and the
+// configured API on first sight. Lookups are best effort: whatever could
+// not be determined stays empty.
+func (e *Enricher) Enrich(ctx context.Context, c *client.Client, host string) Info {
+	e.mu.Lock()
+	if info, ok := e.cache[host]; ok {
+		e.mu.Unlock()
+		return info
+	}
+	e.mu.Unlock()
+
+	var info Info
+	if net.ParseIP(host) == nil {
+		info.CDN = classifyCDN(ctx, host)
+	}
+	if e.APIURL != "" {
+		if ip := resolveOne(ctx, host); ip != "" {
+			info.ASN, info.Org = e.lookupASN(ctx, c, ip)
+		}
+	}
+
+	e.mu.Lock()
+	e.cache[host] = info
+	e.mu.Unlock()
+	return info
+}
+
+// classifyCDN walks the host's CNAME and matches known provider domains.
+func classifyCDN(ctx context.Context, host string) string {
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, host)
+	if err != nil {
+		return ""
+	}
+	cname = strings.TrimSuffix(strings.ToLower(cname), ".")
+	for suffix, provider := range cdnSuffixes {
+		if strings.HasSuffix(cname, suffix) {
+			return provider
+		}
+	}
+	return ""
+}
+
+// resolveOne returns one IP for host; an IP literal is returned as is.
+func resolveOne(ctx context.Context, host string) string {
+	if ip := net.ParseIP(host); ip != nil {
+		return host
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return ""
+	}
+	return addrs[0].IP.String()
+}
+
+// asnResponse covers the field names common IP-intelligence APIs use.
+type asnResponse struct {
+	ASN json.Number `json:"asn"`
+	Org string      `json:"org"`
+	// Alternate spellings seen in the wild.
+	ASNumber     json.Number `json:"as_number"`
+	Organization string      `json:"organization"`
+}
+
+func (e *Enricher) lookupASN(ctx context.Context, c *client.Client, ip string) (asn, org string) {
+	url := strings.ReplaceAll(e.APIURL, "{ip}", ip)
+	resp, err := c.Get(ctx, url)
+	if err != nil || resp.StatusCode != 200 {
+		return "", ""
+	}
+	var doc asnResponse
+	if err := json.Unmarshal(resp.Body, &doc); err != nil {
+		return "", ""
+	}
+	asn = doc.ASN.String()
+	if asn == "" {
+		asn = doc.ASNumber.String()
+	}
+	if asn != "" && !strings.HasPrefix(strings.ToUpper(asn), "AS") {
+		asn = fmt.Sprintf("AS%s", asn)
+	}
+	org = doc.Org
+	if org == "" {
+		org = doc.Organization
+	}
+	return asn, org
+}
@@ -0,0 +1,197 @@
+// Package har records the HTTP exchanges a scan makes and exports them in
+// HAR 1.2 format, so testers can replay discovery traffic in Burp or file
+// it as evidence. Auth-bearing headers are redacted and bodies truncated
+// before anything is written.
+package har
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBody caps how much of each body a HAR entry keeps.
+const DefaultMaxBody = 64 << 10 // 64 KiB
+
+// redactedHeaders are replaced with a placeholder in recorded entries;
+// a HAR file circulates further than the credentials in it should.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// Recorder collects exchanges for export. Safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	maxBody int64
+	entries []entry
+}
+
+// NewRecorder returns a Recorder keeping at most maxBody bytes of each
+// request and response body; zero means DefaultMaxBody.
+func NewRecorder(maxBody int64) *Recorder {
+	if maxBody == 0 {
+		maxBody = DefaultMaxBody
+	}
+	return &Recorder{maxBody: maxBody}
+}
+
+// Record implements the client.Recorder hook.
+func (r *Recorder) Record(req *http.Request, status int, proto string, header http.Header, body []byte, started time.Time, duration time.Duration) {
+	e := entry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Time:            float64(duration) / float64(time.Millisecond),
+		Request: request{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Headers:     headers(req.Header),
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Response: response{
+			Status:      status,
+			StatusText:  http.StatusText(status),
+			HTTPVersion: proto,
+			Headers:     headers(header),
+			Content: content{
+				Size:     len(body),
+				MimeType: header.Get("Content-Type"),
+				Text:     r.truncate(body),
+			},
+			HeadersSize: -1,
+			BodySize:    len(body),
+		},
+		Timings: timings{Send: 0, Wait: float64(duration) / float64(time.Millisecond), Receive: 0},
+	}
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(rc, r.maxBody))
+			rc.Close()
+			e.Request.PostData = &postData{
+				MimeType: req.Header.Get("Content-Type"),
+				Text:     string(data),
+			}
+			e.Request.BodySize = len(data)
+		}
+	}
+	r.mu.Lock()
+	r.entries = append(r.entries, e)
+	r.mu.Unlock()
+}
+
+// WriteFile exports the recorded exchanges as a HAR file at path.
+func (r *Recorder) WriteFile(path string) error {
+	r.mu.Lock()
+	doc := harFile{Log: log{
+		Version: "1.2",
+		Creator: creator{Name: "vespasian", Version: "1.0"},
+		Entries: r.entries,
+	}}
+	r.mu.Unlock()
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// truncate bounds a body to the recorder's limit.
+func (r *Recorder) truncate(body []byte) string {
+	if int64(len(body)) > r.maxBody {
+		body = body[:r.maxBody]
+	}
+	return string(body)
+}
+
+// headers flattens an http.Header into HAR pairs, redacting credentials.
+func headers(h http.Header) []pair {
+	var pairs []pair
+	for name, values := range h {
+		for _, value := range values {
+			if redactedHeaders[http.CanonicalHeaderKey(name)] ||
+				strings.HasPrefix(strings.ToLower(name), "x-auth") {
+				value = "REDACTED"
+			}
+			pairs = append(pairs, pair{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+// HAR 1.2 document layout, limited to the fields vespasian populates.
+
+type harFile struct {
+	Log log `json:"log"`
+}
+
+type log struct {
+	Version string  `json:"version"`
+	Creator creator `json:"creator"`
+	Entries []entry `json:"entries"`
+}
+
+type creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         request  `json:"request"`
+	Response        response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         timings  `json:"timings"`
+}
+
+type request struct {
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	HTTPVersion string    `json:"httpVersion"`
+	Headers     []pair    `json:"headers"`
+	QueryString []pair    `json:"queryString"`
+	PostData    *postData `json:"postData,omitempty"`
+	HeadersSize int       `json:"headersSize"`
+	BodySize    int       `json:"bodySize"`
+}
+
+type response struct {
+	Status      int     `json:"status"`
+	StatusText  string  `json:"statusText"`
+	HTTPVersion string  `json:"httpVersion"`
+	Headers     []pair  `json:"headers"`
+	Content     content `json:"content"`
+	RedirectURL string  `json:"redirectURL"`
+	HeadersSize int     `json:"headersSize"`
+	BodySize    int     `json:"bodySize"`
+}
+
+type content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type postData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type pair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
@@ -0,0 +1,166 @@
+// Package history tracks endpoint lifetimes across scans. Each endpoint
+// fingerprint (method plus URL) carries first-seen and last-seen
+// timestamps, so repeated scans of the same estate reveal when endpoints
+// appeared and when they quietly disappeared — the raw material of
+// attack-surface management.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Record is one endpoint's lifetime across scans.
+type Record struct {
+	URL       string     `json:"url"`
+	Method    string     `json:"method"`
+	FirstSeen types.Time `json:"first_seen"`
+	LastSeen  types.Time `json:"last_seen"`
+	Scans     int        `json:"scans"`
+}
+
+// Store is a JSON-file-backed endpoint history.
+type Store struct {
+	path     string
+	lastScan types.Time
+	records  map[string]*Record
+}
+
+// storeFile is the on-disk layout.
+type storeFile struct {
+	LastScan types.Time `json:"last_scan"`
+	Records  []*Record  `json:"records"`
+}
+
+// Open loads the history at path, starting empty if the file does not
+// exist yet.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, records: map[string]*Record{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("history %s: %w", path, err)
+	}
+	s.lastScan = file.LastScan
+	for _, record := range file.Records {
+		s.records[fingerprint(record.Method, record.URL)] = record
+	}
+	return s, nil
+}
+
+// Update marks every endpoint as seen at now, creating records on first
+// sight.
+func (s *Store) Update(endpoints []types.Endpoint, now time.Time) {
+	seen := types.NewTime(now.UTC())
+	s.lastScan = seen
+	for _, endpoint := range endpoints {
+		key := fingerprint(endpoint.Method, endpoint.URL)
+		record := s.records[key]
+		if record == nil {
+			record = &Record{
+				URL:       endpoint.URL,
+				Method:    endpoint.Method,
+				FirstSeen: seen,
+			}
+			s.records[key] = record
+		}
+		record.LastSeen = seen
+		record.Scans++
+	}
+}
+
+// Save writes the history back to its file.
+func (s *Store) Save() error {
+	file := storeFile{LastScan: s.lastScan, Records: make([]*Record, 0, len(s.records))}
+	for _, record := range s.records {
+		file.Records = append(file.Records, record)
+	}
+	sort.Slice(file.Records, func(i, j int) bool {
+		if file.Records[i].URL != file.Records[j].URL {
+			return file.Records[i].URL < file.Records[j].URL
+		}
+		return file.Records[i].Method < file.Records[j].Method
+	})
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0o644)
+}
+
+// Lookup returns the records whose URL contains the query, method-prefixed
+// queries ("GET https://…") matching exactly.
+func (s *Store) Lookup(query string) []Record {
+	var matches []Record
+	for _, record := range s.records {
+		if fingerprint(record.Method, record.URL) == query || (query != "" && strings.Contains(record.URL, query)) {
+			matches = append(matches, *record)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].URL != matches[j].URL {
+			return matches[i].URL < matches[j].URL
+		}
+		return matches[i].Method < matches[j].Method
+	})
+	return matches
+}
+
+// WeekChurn counts endpoint appearances and disappearances in one ISO
+// week.
+type WeekChurn struct {
+	Week    string `json:"week"`
+	New     int    `json:"new"`
+	Removed int    `json:"removed"`
+}
+
+// Churn reports per-week endpoint churn. An endpoint is new in the week
+// it was first seen and removed in the week of its last sighting, unless
+// that sighting was the most recent scan — then it is still live.
+func (s *Store) Churn() []WeekChurn {
+	weeks := map[string]*WeekChurn{}
+	week := func(t types.Time) *WeekChurn {
+		key := weekKey(t.Time)
+		entry := weeks[key]
+		if entry == nil {
+			entry = &WeekChurn{Week: key}
+			weeks[key] = entry
+		}
+		return entry
+	}
+	for _, record := range s.records {
+		week(record.FirstSeen).New++
+		if record.LastSeen.Before(s.lastScan.Time) {
+			week(record.LastSeen).Removed++
+		}
+	}
+	churn := make([]WeekChurn, 0, len(weeks))
+	for _, entry := range weeks {
+		churn = append(churn, *entry)
+	}
+	sort.Slice(churn, func(i, j int) bool { return churn[i].Week < churn[j].Week })
+	return churn
+}
+
+// fingerprint identifies an endpoint across scans.
+func fingerprint(method, url string) string {
+	return method + " " + url
+}
+
+// weekKey renders a time as its ISO week, e.g. "2026-W35".
+func weekKey(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
@@ -0,0 +1,89 @@
+// Package inventory organizes discovered endpoints for presentation:
+// large scans routinely produce thousands of endpoints, and grouping them
+// into path families keeps the output navigable.
+package inventory
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Cluster is a family of endpoints sharing the same path structure, e.g.
+// "/api/v1/orders/*" covering every per-order endpoint.
+type Cluster struct {
+	// Pattern is the generalized path with variable segments replaced
+	// by "*", prefixed by scheme and host.
+	Pattern string
+	// Endpoints are the members, in their original order.
+	Endpoints []types.Endpoint
+}
+
+var (
+	numericRe = regexp.MustCompile(`^[0-9]+$`)
+	uuidRe    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexRe     = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+)
+
+// ClusterEndpoints groups endpoints by path-structure similarity. Segments
+// that look like identifiers (numbers, UUIDs, long hex tokens, template
+// parameters) are generalized to "*" before grouping. Clusters are sorted
+// by size, largest first, then by pattern.
+func ClusterEndpoints(endpoints []types.Endpoint) []Cluster {
+	groups := map[string][]types.Endpoint{}
+	for _, endpoint := range endpoints {
+		groups[pattern(endpoint.URL)] = append(groups[pattern(endpoint.URL)], endpoint)
+	}
+	clusters := make([]Cluster, 0, len(groups))
+	for p, members := range groups {
+		clusters = append(clusters, Cluster{Pattern: p, Endpoints: members})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Endpoints) != len(clusters[j].Endpoints) {
+			return len(clusters[i].Endpoints) > len(clusters[j].Endpoints)
+		}
+		return clusters[i].Pattern < clusters[j].Pattern
+	})
+	return clusters
+}
+
+// pattern generalizes a URL to its structural form.
+func pattern(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if variable(segment) {
+			segments[i] = "*"
+		}
+	}
+	base := u.Path
+	if u.Scheme != "" && u.Host != "" {
+		base = u.Scheme + "://" + u.Host
+	} else {
+		base = ""
+	}
+	return base + strings.Join(segments, "/")
+}
+
+// variable reports whether a path segment looks like an identifier rather
+// than a structural token.
+func variable(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return true
+	}
+	if strings.HasPrefix(segment, ":") {
+		return true
+	}
+	return numericRe.MatchString(segment) ||
+		uuidRe.MatchString(segment) ||
+		hexRe.MatchString(segment)
+}
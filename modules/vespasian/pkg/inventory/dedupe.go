@@ -0,0 +1,125 @@
+package inventory
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// DedupeEndpoints collapses endpoints that differ only in path parameter
+// values: /users/123 and /users/456 become one /users/{id} endpoint.
+// Identifier segments (numbers, UUIDs, long hex tokens, plus any segment
+// matching the caller's extra patterns) are templated to {id} before
+// comparison. Duplicates merge into the first-seen endpoint rather than
+// disappearing: the survivor keeps first-seen ordering and one concrete
+// example URL, absorbs metadata keys it lacked, takes the maximum
+// confidence, and records every probe that reported it — an endpoint
+// corroborated by three probes is a different claim than one probe's
+// guess, and the corroboration count preserves that.
+func DedupeEndpoints(endpoints []types.Endpoint, extra []*regexp.Regexp) []types.Endpoint {
+	type key struct {
+		url    string
+		method string
+	}
+	seen := map[key]int{}
+	sources := map[int][]string{}
+	deduped := make([]types.Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		templated, changed := templatePath(endpoint.URL, extra)
+		k := key{url: templated, method: endpoint.Method}
+		if i, ok := seen[k]; ok {
+			kept := &deduped[i]
+			if kept.Metadata == nil {
+				kept.Metadata = map[string]string{}
+			}
+			// Count how many concrete endpoints the template absorbed.
+			kept.Metadata["merged"] = incr(kept.Metadata["merged"])
+			if endpoint.Confidence > kept.Confidence {
+				kept.Confidence = endpoint.Confidence
+			}
+			// The duplicate's metadata may carry evidence the survivor
+			// lacks (auth verdicts, risk flags); keep the survivor's
+			// values on conflict.
+			for mk, mv := range endpoint.Metadata {
+				if _, exists := kept.Metadata[mk]; !exists {
+					kept.Metadata[mk] = mv
+				}
+			}
+			if !contains(sources[i], endpoint.Source) {
+				sources[i] = append(sources[i], endpoint.Source)
+				kept.Metadata["sources"] = strings.Join(sources[i], ", ")
+				kept.Metadata["corroboration"] = strconv.Itoa(len(sources[i]))
+			}
+			continue
+		}
+		if changed {
+			if endpoint.Metadata == nil {
+				endpoint.Metadata = map[string]string{}
+			}
+			endpoint.Metadata["example"] = endpoint.URL
+			endpoint.URL = templated
+		}
+		seen[k] = len(deduped)
+		sources[len(deduped)] = []string{endpoint.Source}
+		deduped = append(deduped, endpoint)
+	}
+	return deduped
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// templatePath rewrites identifier segments of a URL path to {id} and
+// reports whether anything changed.
+func templatePath(raw string, extra []*regexp.Regexp) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw, false
+	}
+	segments := strings.Split(u.Path, "/")
+	changed := false
+	for i, segment := range segments {
+		if segment == "" || segment == "{id}" {
+			continue
+		}
+		if variable(segment) || matchesAny(segment, extra) {
+			segments[i] = "{id}"
+			changed = true
+		}
+	}
+	if !changed {
+		return raw, false
+	}
+	u.Path = strings.Join(segments, "/")
+	// url.URL.String percent-encodes the braces; restore them so
+	// templated URLs match spec-derived paths like /users/{id}.
+	return strings.ReplaceAll(u.String(), "%7Bid%7D", "{id}"), true
+}
+
+func matchesAny(segment string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// incr bumps a numeric metadata counter stored as a string, counting the
+// original endpoint as the first member.
+func incr(value string) string {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		n = 1
+	}
+	return strconv.Itoa(n + 1)
+}
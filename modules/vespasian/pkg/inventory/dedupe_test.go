@@ -0,0 +1,63 @@
+package inventory_test
+
+import (
+	"testing"
+
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TestDedupeMergesDuplicates asserts collapsing keeps the first-seen
+// endpoint and merges what the duplicates knew: missing metadata keys,
+// the maximum confidence, and the corroborating sources.
+func TestDedupeMergesDuplicates(t *testing.T) {
+	endpoints := []types.Endpoint{
+		{
+			URL: "http://target.invalid/users/123", Method: "GET",
+			Source: "crawler", Confidence: types.ConfidenceLow,
+			Metadata: map[string]string{"origin": "bundle"},
+		},
+		{
+			URL: "http://target.invalid/users/456", Method: "GET",
+			Source: "openapi", Confidence: types.ConfidenceHigh,
+			Metadata: map[string]string{"origin": "spec", "auth": "required"},
+		},
+	}
+
+	deduped := inventory.DedupeEndpoints(endpoints, nil)
+	if len(deduped) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(deduped))
+	}
+	survivor := deduped[0]
+	if survivor.URL != "http://target.invalid/users/{id}" {
+		t.Errorf("survivor URL = %s, want the templated path", survivor.URL)
+	}
+	if survivor.Confidence != types.ConfidenceHigh {
+		t.Errorf("survivor confidence = %v, want the maximum %v", survivor.Confidence, types.ConfidenceHigh)
+	}
+	if survivor.Metadata["origin"] != "bundle" {
+		t.Errorf("survivor origin = %q, want the first-seen value kept on conflict", survivor.Metadata["origin"])
+	}
+	if survivor.Metadata["auth"] != "required" {
+		t.Errorf("survivor did not absorb the duplicate's auth verdict")
+	}
+	if survivor.Metadata["sources"] != "crawler, openapi" || survivor.Metadata["corroboration"] != "2" {
+		t.Errorf("sources %q corroboration %q, want both probes recorded",
+			survivor.Metadata["sources"], survivor.Metadata["corroboration"])
+	}
+	if survivor.Metadata["example"] != "http://target.invalid/users/123" {
+		t.Errorf("example = %q, want the survivor's concrete URL", survivor.Metadata["example"])
+	}
+}
+
+// TestDedupeKeepsDistinctPaths asserts non-identifier segments are never
+// templated together.
+func TestDedupeKeepsDistinctPaths(t *testing.T) {
+	endpoints := []types.Endpoint{
+		{URL: "http://target.invalid/users", Method: "GET", Source: "openapi"},
+		{URL: "http://target.invalid/orders", Method: "GET", Source: "openapi"},
+	}
+	if deduped := inventory.DedupeEndpoints(endpoints, nil); len(deduped) != 2 {
+		t.Errorf("got %d endpoints, want both paths kept", len(deduped))
+	}
+}
@@ -0,0 +1,33 @@
+package inventory
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// HostGroup collects every endpoint discovered on one host.
+type HostGroup struct {
+	Host      string           `json:"host"`
+	Endpoints []types.Endpoint `json:"endpoints"`
+}
+
+// GroupByHost splits endpoints by the host their URL points at, sorted by
+// host name. Endpoints with unparsable URLs group under "".
+func GroupByHost(endpoints []types.Endpoint) []HostGroup {
+	byHost := map[string][]types.Endpoint{}
+	for _, endpoint := range endpoints {
+		host := ""
+		if u, err := url.Parse(endpoint.URL); err == nil {
+			host = u.Host
+		}
+		byHost[host] = append(byHost[host], endpoint)
+	}
+	groups := make([]HostGroup, 0, len(byHost))
+	for host, members := range byHost {
+		groups = append(groups, HostGroup{Host: host, Endpoints: members})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Host < groups[j].Host })
+	return groups
+}
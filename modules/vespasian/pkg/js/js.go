@@ -0,0 +1,339 @@
+// Package js extracts API endpoints from JavaScript and TypeScript
+// sources. Instead of grepping for URL-shaped strings, it lexes the
+// source, folds simple string constants, and matches the call shapes
+// frameworks use to issue requests — fetch, XMLHttpRequest.open, the
+// axios/jQuery/Angular HttpClient verb helpers, $.ajax option objects —
+// plus router path tables. That recovers concatenated URLs and the HTTP
+// method alongside each endpoint, which string matching cannot.
+package js
+
+import "strings"
+
+// Call is one request site or route recovered from a script.
+type Call struct {
+	// URL is the folded request URL. Unresolvable parts (variables,
+	// template interpolations) appear as {name} placeholders.
+	URL string
+	// Method is the upper-case HTTP verb when the call shape names one.
+	Method string
+	// Kind records which shape matched: "fetch", "xhr", "ajax", "verb",
+	// or "route".
+	Kind string
+}
+
+// httpVerbs maps verb-helper method names to the HTTP method they send.
+var httpVerbs = map[string]string{
+	"get":     "GET",
+	"getJSON": "GET",
+	"post":    "POST",
+	"put":     "PUT",
+	"delete":  "DELETE",
+	"patch":   "PATCH",
+	"head":    "HEAD",
+	"options": "OPTIONS",
+}
+
+// Extract mines src for request sites and router paths.
+func Extract(src []byte) []Call {
+	tokens := lex(src)
+	consts := foldConstants(tokens)
+
+	var calls []Call
+	seen := map[string]bool{}
+	add := func(call Call) {
+		if !endpointLike(call.URL) {
+			return
+		}
+		key := call.Method + " " + call.URL
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		calls = append(calls, call)
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		if call, ok := matchFetch(tokens, i, consts); ok {
+			add(call)
+			continue
+		}
+		if call, ok := matchXHROpen(tokens, i, consts); ok {
+			add(call)
+			continue
+		}
+		if call, ok := matchAjax(tokens, i, consts); ok {
+			add(call)
+			continue
+		}
+		if call, ok := matchVerbHelper(tokens, i, consts); ok {
+			add(call)
+			continue
+		}
+		if call, ok := matchRoute(tokens, i); ok {
+			add(call)
+		}
+	}
+	return calls
+}
+
+// foldConstants collects const/let/var declarations whose initializer is
+// a string expression, resolving earlier constants so chains like
+// const base = "/api"; const users = base + "/users" fold fully.
+func foldConstants(tokens []token) map[string]string {
+	consts := map[string]string{}
+	for i := 0; i+3 < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind != identTok || (t.text != "const" && t.text != "let" && t.text != "var") {
+			continue
+		}
+		name := tokens[i+1]
+		if name.kind != identTok || !isPunct(tokens[i+2], "=") {
+			continue
+		}
+		value, _, literal := stringExpr(tokens, i+3, consts)
+		if literal {
+			consts[name.text] = value
+		}
+	}
+	return consts
+}
+
+// stringExpr folds a string concatenation starting at i: literals and
+// known constants joined by +, with unknown identifiers and member paths
+// becoming {name} placeholders. literal reports whether at least one
+// term was an actual string, which separates "/api" + id from arbitrary
+// arithmetic.
+func stringExpr(tokens []token, i int, consts map[string]string) (value string, next int, literal bool) {
+	var b strings.Builder
+	for i < len(tokens) {
+		switch tokens[i].kind {
+		case stringTok:
+			b.WriteString(resolvePlaceholders(tokens[i].text, consts))
+			literal = true
+			i++
+		case identTok:
+			name := tokens[i].text
+			i++
+			for i+1 < len(tokens) && isPunct(tokens[i], ".") && tokens[i+1].kind == identTok {
+				name += "." + tokens[i+1].text
+				i += 2
+			}
+			if folded, ok := consts[name]; ok {
+				b.WriteString(folded)
+				literal = true
+			} else {
+				b.WriteString("{" + name + "}")
+			}
+		default:
+			return b.String(), i, literal
+		}
+		if i < len(tokens) && isPunct(tokens[i], "+") {
+			i++
+			continue
+		}
+		return b.String(), i, literal
+	}
+	return b.String(), i, literal
+}
+
+// resolvePlaceholders substitutes known constants into the {name}
+// placeholders template interpolations leave behind, so `${base}/users`
+// folds the same way base + "/users" does.
+func resolvePlaceholders(value string, consts map[string]string) string {
+	for strings.Contains(value, "{") {
+		start := strings.Index(value, "{")
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			break
+		}
+		name := value[start+1 : start+end]
+		folded, ok := consts[name]
+		if ok && strings.Contains(folded, "{"+name+"}") {
+			// A degenerate self-referential constant; substituting it
+			// would never terminate.
+			ok = false
+		}
+		if !ok {
+			// Leave unknown placeholders alone; scan past this one.
+			rest := resolvePlaceholders(value[start+end+1:], consts)
+			return value[:start+end+1] + rest
+		}
+		value = value[:start] + folded + value[start+end+1:]
+	}
+	return value
+}
+
+// matchFetch recognizes fetch(url) and fetch(url, {method: "..."}).
+func matchFetch(tokens []token, i int, consts map[string]string) (Call, bool) {
+	if tokens[i].kind != identTok || tokens[i].text != "fetch" ||
+		i+1 >= len(tokens) || !isPunct(tokens[i+1], "(") {
+		return Call{}, false
+	}
+	url, next, literal := stringExpr(tokens, i+2, consts)
+	if !literal {
+		return Call{}, false
+	}
+	call := Call{URL: url, Method: "GET", Kind: "fetch"}
+	if method, ok := optionValue(tokens, next, []string{"method"}); ok {
+		call.Method = strings.ToUpper(method)
+	}
+	return call, true
+}
+
+// matchXHROpen recognizes xhr.open("METHOD", url).
+func matchXHROpen(tokens []token, i int, consts map[string]string) (Call, bool) {
+	if !isPunct(tokens[i], ".") || i+4 >= len(tokens) ||
+		tokens[i+1].kind != identTok || tokens[i+1].text != "open" ||
+		!isPunct(tokens[i+2], "(") || tokens[i+3].kind != stringTok ||
+		!isPunct(tokens[i+4], ",") {
+		return Call{}, false
+	}
+	method := strings.ToUpper(tokens[i+3].text)
+	if !knownMethod(method) {
+		return Call{}, false
+	}
+	url, _, literal := stringExpr(tokens, i+5, consts)
+	if !literal {
+		return Call{}, false
+	}
+	return Call{URL: url, Method: method, Kind: "xhr"}, true
+}
+
+// matchAjax recognizes $.ajax({url: ..., type: "..."}) and the
+// equivalent method: key jQuery accepts.
+func matchAjax(tokens []token, i int, consts map[string]string) (Call, bool) {
+	if !isPunct(tokens[i], ".") || i+3 >= len(tokens) ||
+		tokens[i+1].kind != identTok || tokens[i+1].text != "ajax" ||
+		!isPunct(tokens[i+2], "(") || !isPunct(tokens[i+3], "{") {
+		return Call{}, false
+	}
+	call := Call{Method: "GET", Kind: "ajax"}
+	depth := 0
+	for j := i + 3; j < len(tokens); j++ {
+		switch {
+		case isPunct(tokens[j], "{"):
+			depth++
+		case isPunct(tokens[j], "}"):
+			depth--
+			if depth == 0 {
+				if call.URL == "" {
+					return Call{}, false
+				}
+				return call, true
+			}
+		case depth == 1 && objectKey(tokens, j, "url"):
+			if url, _, literal := stringExpr(tokens, j+2, consts); literal {
+				call.URL = url
+			}
+		case depth == 1 && (objectKey(tokens, j, "type") || objectKey(tokens, j, "method")):
+			if tokens[j+2].kind == stringTok {
+				call.Method = strings.ToUpper(tokens[j+2].text)
+			}
+		}
+	}
+	return Call{}, false
+}
+
+// matchVerbHelper recognizes receiver.get(url) and friends: axios,
+// Angular's HttpClient, jQuery's $.get/$.post, and every wrapper that
+// copies the convention. The endpointLike filter discards the .get(...)
+// calls that are map lookups rather than requests.
+func matchVerbHelper(tokens []token, i int, consts map[string]string) (Call, bool) {
+	if !isPunct(tokens[i], ".") || i+2 >= len(tokens) || tokens[i+1].kind != identTok {
+		return Call{}, false
+	}
+	method, ok := httpVerbs[tokens[i+1].text]
+	if !ok || !isPunct(tokens[i+2], "(") {
+		return Call{}, false
+	}
+	url, _, literal := stringExpr(tokens, i+3, consts)
+	if !literal {
+		return Call{}, false
+	}
+	return Call{URL: url, Method: method, Kind: "verb"}, true
+}
+
+// matchRoute recognizes path: "/..." entries in router tables (Angular,
+// Vue Router, React Router object routes). Only rooted literal paths
+// count, and only inside an object literal.
+func matchRoute(tokens []token, i int) (Call, bool) {
+	if i == 0 || !(isPunct(tokens[i-1], "{") || isPunct(tokens[i-1], ",")) {
+		return Call{}, false
+	}
+	if !objectKey(tokens, i, "path") && !objectKey(tokens, i, "route") {
+		return Call{}, false
+	}
+	value := tokens[i+2]
+	if value.kind != stringTok || !strings.HasPrefix(value.text, "/") {
+		return Call{}, false
+	}
+	return Call{URL: value.text, Kind: "route"}, true
+}
+
+// optionValue scans forward from an argument list for key: "value"
+// inside the call's option object, stopping at the call's closing paren.
+func optionValue(tokens []token, i int, keys []string) (string, bool) {
+	depth := 1 // inside the call's parentheses
+	for ; i < len(tokens) && depth > 0; i++ {
+		switch {
+		case isPunct(tokens[i], "("):
+			depth++
+		case isPunct(tokens[i], ")"):
+			depth--
+		default:
+			for _, key := range keys {
+				if objectKey(tokens, i, key) && tokens[i+2].kind == stringTok {
+					return tokens[i+2].text, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// objectKey reports whether tokens[i] is the object key name followed by
+// a colon. Keys appear as identifiers or quoted strings.
+func objectKey(tokens []token, i int, name string) bool {
+	return i+2 < len(tokens) &&
+		(tokens[i].kind == identTok || tokens[i].kind == stringTok) &&
+		tokens[i].text == name && isPunct(tokens[i+1], ":")
+}
+
+func isPunct(t token, text string) bool {
+	return t.kind == punctTok && t.text == text
+}
+
+func knownMethod(method string) bool {
+	switch method {
+	case "GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// staticExtensions are asset suffixes that are never API endpoints.
+var staticExtensions = []string{
+	".css", ".png", ".jpg", ".jpeg", ".gif", ".svg", ".ico",
+	".woff", ".woff2", ".ttf", ".eot", ".map",
+}
+
+// endpointLike reports whether a folded URL is worth reporting: a rooted
+// path or absolute URL that is not a static asset and did not collapse
+// to placeholders alone.
+func endpointLike(url string) bool {
+	if url == "" || url == "/" || strings.ContainsAny(url, " \t\n") {
+		return false
+	}
+	if !strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "http://") &&
+		!strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "ws://") &&
+		!strings.HasPrefix(url, "wss://") {
+		return false
+	}
+	lower := strings.ToLower(url)
+	for _, ext := range staticExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package js_test
+
+import (
+	"testing"
+
+	"github.com/praetorian-inc/vespasian/pkg/js"
+)
+
+// find returns the first extracted call for url, or nil.
+func find(calls []js.Call, url string) *js.Call {
+	for i := range calls {
+		if calls[i].URL == url {
+			return &calls[i]
+		}
+	}
+	return nil
+}
+
+// TestExtractCallShapes exercises every call shape the extractor
+// recognizes, including the constant folding and template placeholders
+// plain string matching cannot recover.
+func TestExtractCallShapes(t *testing.T) {
+	src := "const base = \"/api/v2\";\n" +
+		"fetch(\"/api/users\");\n" +
+		"fetch(base + \"/orders\", {method: \"post\"});\n" +
+		"fetch(`/api/users/${id}/avatar`);\n" +
+		"xhr.open(\"DELETE\", \"/api/items\", true);\n" +
+		"$.ajax({url: \"/api/legacy\", type: \"PUT\"});\n" +
+		"http.get(\"/api/health\");\n" +
+		"const routes = [{path: \"/admin/settings\", component: Settings}];\n" +
+		"fetch(\"/logo.png\");\n"
+	calls := js.Extract([]byte(src))
+
+	expected := map[string]struct{ method, kind string }{
+		"/api/users":             {"GET", "fetch"},
+		"/api/v2/orders":         {"POST", "fetch"},
+		"/api/users/{id}/avatar": {"GET", "fetch"},
+		"/api/items":             {"DELETE", "xhr"},
+		"/api/legacy":            {"PUT", "ajax"},
+		"/api/health":            {"GET", "verb"},
+		"/admin/settings":        {"", "route"},
+	}
+	for url, want := range expected {
+		call := find(calls, url)
+		if call == nil {
+			t.Errorf("no call extracted for %s", url)
+			continue
+		}
+		if call.Method != want.method || call.Kind != want.kind {
+			t.Errorf("%s extracted as %s %s, want %s %s", url, call.Method, call.Kind, want.method, want.kind)
+		}
+	}
+	if call := find(calls, "/logo.png"); call != nil {
+		t.Errorf("static asset /logo.png extracted as %s call", call.Kind)
+	}
+}
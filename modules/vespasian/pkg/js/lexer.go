@@ -0,0 +1,218 @@
+package js
+
+import "strings"
+
+// tokenKind classifies lexer output. The lexer understands just enough
+// JavaScript to keep string literals intact: comments, the three quote
+// styles, template interpolation, and regex literals that would
+// otherwise swallow quotes.
+type tokenKind int
+
+const (
+	identTok tokenKind = iota
+	stringTok
+	numberTok
+	punctTok
+)
+
+type token struct {
+	kind tokenKind
+	// text is the identifier name, the punctuation character, or the
+	// decoded string value. Template interpolations appear in string
+	// values as {name} placeholders.
+	text string
+}
+
+// lex tokenizes JavaScript or TypeScript source. It is lossy everywhere
+// except strings, identifiers, and punctuation — exactly what endpoint
+// extraction reads.
+func lex(src []byte) []token {
+	var tokens []token
+	s := string(src)
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && i+1 < len(s) && s[i+1] == '/':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				i = len(s)
+			} else {
+				i += end + 4
+			}
+		case c == '\'' || c == '"':
+			value, next := lexString(s, i+1, c)
+			tokens = append(tokens, token{kind: stringTok, text: value})
+			i = next
+		case c == '`':
+			value, next := lexTemplate(s, i+1)
+			tokens = append(tokens, token{kind: stringTok, text: value})
+			i = next
+		case c == '/' && !divisionContext(tokens):
+			i = skipRegex(s, i+1)
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && isIdentPart(s[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: identTok, text: s[i:j]})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(s) && (isIdentPart(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: numberTok, text: s[i:j]})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: punctTok, text: string(c)})
+			i++
+		}
+	}
+	return tokens
+}
+
+// lexString consumes a quoted string body and returns its decoded value
+// and the index after the closing quote.
+func lexString(s string, i int, quote byte) (string, int) {
+	var b strings.Builder
+	for i < len(s) {
+		switch s[i] {
+		case quote:
+			return b.String(), i + 1
+		case '\\':
+			if i+1 < len(s) {
+				// Only the escapes that appear in URLs need decoding;
+				// everything else passes through literally.
+				switch s[i+1] {
+				case '/', '\\', quote:
+					b.WriteByte(s[i+1])
+				default:
+					b.WriteByte('\\')
+					b.WriteByte(s[i+1])
+				}
+				i += 2
+				continue
+			}
+			i++
+		case '\n':
+			// Unterminated string: stop at the line break.
+			return b.String(), i + 1
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String(), i
+}
+
+// lexTemplate consumes a template literal body. Each ${...}
+// interpolation is replaced with a {name} placeholder: the bare
+// identifier when the expression is one, "expr" otherwise.
+func lexTemplate(s string, i int) (string, int) {
+	var b strings.Builder
+	for i < len(s) {
+		switch {
+		case s[i] == '`':
+			return b.String(), i + 1
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteByte(s[i+1])
+			i += 2
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth := 1
+			j := i + 2
+			for j < len(s) && depth > 0 {
+				switch s[j] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				}
+				j++
+			}
+			expr := strings.TrimSpace(s[i+2 : j-1])
+			if !isSimpleName(expr) {
+				expr = "expr"
+			}
+			b.WriteString("{" + expr + "}")
+			i = j
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String(), i
+}
+
+// skipRegex consumes a regex literal body, honoring escapes and
+// character classes, and returns the index after the closing slash.
+func skipRegex(s string, i int) int {
+	inClass := false
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return i + 1
+			}
+		case '\n':
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// divisionContext reports whether a slash at this point is division
+// rather than the start of a regex literal: division follows a value
+// (identifier, number, string, or a closing bracket).
+func divisionContext(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	switch last.kind {
+	case identTok, numberTok, stringTok:
+		return last.text != "return" && last.text != "typeof"
+	case punctTok:
+		return last.text == ")" || last.text == "]"
+	}
+	return false
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// isSimpleName reports whether expr is a bare identifier or a dotted
+// member path, usable verbatim as a placeholder name.
+func isSimpleName(expr string) bool {
+	if expr == "" {
+		return false
+	}
+	for _, part := range strings.Split(expr, ".") {
+		if part == "" || !isIdentStart(part[0]) {
+			return false
+		}
+		for i := 1; i < len(part); i++ {
+			if !isIdentPart(part[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+// Package knowledge centralizes what the probes collectively know about
+// where services live: which ports are worth HTTP probing and the path
+// dictionaries shared across detectors. Probes reference this package
+// instead of carrying private copies, so extending the port surface or a
+// path list is one edit, not six.
+package knowledge
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Port profile names selectable per scan.
+const (
+	// WebDefault is the classic web surface: 80, 443, 8080, 8443.
+	WebDefault = "web-default"
+	// APIExtended adds the ports development frameworks and internal
+	// API gateways habitually bind.
+	APIExtended = "api-extended"
+	// AllPorts accepts any port, for targets imported with explicit
+	// port information from a port scan.
+	AllPorts = "all-ports"
+)
+
+// portProfiles maps each profile to its accepted ports. Port 0 stands
+// for "no port given" and is accepted everywhere. A nil set accepts
+// every port.
+var portProfiles = map[string]map[int]bool{
+	WebDefault: {0: true, 80: true, 443: true, 8080: true, 8443: true},
+	APIExtended: {
+		0: true, 80: true, 443: true,
+		3000: true, 4000: true, 5000: true, 8000: true,
+		8080: true, 8081: true, 8443: true, 8888: true,
+		9000: true, 9090: true,
+	},
+	AllPorts: nil,
+}
+
+var (
+	mu      sync.RWMutex
+	profile = WebDefault
+)
+
+// SetPortProfile selects the port profile used by every probe's
+// Accepts for the rest of the scan.
+func SetPortProfile(name string) error {
+	if _, ok := portProfiles[name]; !ok {
+		return fmt.Errorf("knowledge: unknown port profile %q", name)
+	}
+	mu.Lock()
+	profile = name
+	mu.Unlock()
+	return nil
+}
+
+// AcceptsTarget reports whether HTTP probes should run against target.
+// Service detection verdicts and explicit URL schemes win when present;
+// the port profile is the fallback for targets nothing has touched yet.
+func AcceptsTarget(target types.Target) bool {
+	if target.Service != nil {
+		return target.Service.HTTP
+	}
+	if target.Scheme != "" {
+		return true
+	}
+	return AcceptsHTTP(target.Port)
+}
+
+// AcceptsHTTP reports whether the active profile considers port worth
+// HTTP probing.
+func AcceptsHTTP(port int) bool {
+	mu.RLock()
+	ports := portProfiles[profile]
+	mu.RUnlock()
+	if ports == nil {
+		return true
+	}
+	return ports[port]
+}
+
+// Shared path dictionaries. Probes own paths tied to their protocol
+// internals; the lists here are the ones several detectors consult.
+var (
+	// GraphQLPaths are the locations GraphQL services are commonly
+	// served from.
+	GraphQLPaths = []string{
+		"/graphql",
+		"/api/graphql",
+		"/v1/graphql",
+		"/query",
+		"/graphql/v1",
+	}
+	// SpecPaths are the locations OpenAPI/Swagger documents are
+	// commonly served from.
+	SpecPaths = []string{
+		"/openapi.json",
+		"/swagger.json",
+		"/v2/api-docs",
+		"/v3/api-docs",
+		"/swagger/v1/swagger.json",
+		"/api-docs",
+		"/api/openapi.json",
+		"/api/swagger.json",
+	}
+	// WSDLPaths are the locations WSDL documents are commonly served
+	// from.
+	WSDLPaths = []string{
+		"/?wsdl",
+		"/service?wsdl",
+		"/services?wsdl",
+		"/ws?wsdl",
+		"/soap?wsdl",
+		"/Service.asmx?WSDL",
+	}
+	// WebSocketPaths are the locations WebSocket endpoints are commonly
+	// served from.
+	WebSocketPaths = []string{
+		"/ws",
+		"/websocket",
+		"/socket",
+		"/cable",
+		"/socket.io/",
+		"/sockjs/info",
+		"/hub",
+	}
+)
@@ -0,0 +1,143 @@
+// Package metrics collects operational counters and gauges — probe runs,
+// HTTP request volume, crawl frontier depth — and exposes them in the
+// Prometheus text format so platform teams can scrape a long-running
+// scan. vespasian vendors no telemetry SDK; this is the stdlib-only
+// subset of that surface, collected unconditionally (the instruments are
+// a map update apiece) and served only when an address is configured.
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// labelSeparator joins label values into a map key; \xff cannot appear in
+// a valid label value.
+const labelSeparator = "\xff"
+
+// metric is one named instrument with its per-label-set values.
+type metric struct {
+	name       string
+	help       string
+	kind       string // "counter" or "gauge"
+	labelNames []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*metric
+)
+
+func register(name, help, kind string, labelNames []string) *metric {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, m := range registry {
+		if m.name == name {
+			return m
+		}
+	}
+	m := &metric{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		values:     map[string]float64{},
+	}
+	registry = append(registry, m)
+	return m
+}
+
+// Counter is a monotonically increasing instrument.
+type Counter struct{ m *metric }
+
+// NewCounter registers a counter; registering the same name twice returns
+// the existing instrument.
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{m: register(name, help, "counter", labelNames)}
+}
+
+// Inc adds one to the counter for the given label values.
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds v to the counter for the given label values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.m.mu.Lock()
+	c.m.values[strings.Join(labelValues, labelSeparator)] += v
+	c.m.mu.Unlock()
+}
+
+// Gauge is an instrument whose value moves both ways.
+type Gauge struct{ m *metric }
+
+// NewGauge registers a gauge; registering the same name twice returns the
+// existing instrument.
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{m: register(name, help, "gauge", labelNames)}
+}
+
+// Set records the gauge's current value for the given label values.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.m.mu.Lock()
+	g.m.values[strings.Join(labelValues, labelSeparator)] = v
+	g.m.mu.Unlock()
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		registryMu.Lock()
+		snapshot := make([]*metric, len(registry))
+		copy(snapshot, registry)
+		registryMu.Unlock()
+		for _, m := range snapshot {
+			fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+			fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.kind)
+			m.mu.Lock()
+			keys := make([]string, 0, len(m.values))
+			for key := range m.values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s%s %g\n", m.name, renderLabels(m.labelNames, key), m.values[key])
+			}
+			m.mu.Unlock()
+		}
+	})
+}
+
+// Serve exposes /metrics on addr in a background goroutine and returns
+// once the listener is handed off; the scan never blocks on a scraper.
+func Serve(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("metrics: %v", err)
+		}
+	}()
+}
+
+func renderLabels(names []string, key string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	values := strings.Split(key, labelSeparator)
+	pairs := make([]string, 0, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
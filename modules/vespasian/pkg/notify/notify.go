@@ -0,0 +1,101 @@
+// Package notify posts a scan summary to a configured webhook when a
+// scan completes, so long engagements surface results without anyone
+// polling for them.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Trigger conditions for sending the notification.
+const (
+	// OnAlways notifies on every completed scan.
+	OnAlways = "always"
+	// OnHigh notifies only when high-confidence endpoints were found.
+	OnHigh = "high"
+)
+
+// Notifier posts scan summaries to a webhook URL.
+type Notifier struct {
+	// URL receives the POST.
+	URL string
+	// Slack switches the payload to the Slack-compatible {"text": ...}
+	// form; off sends a structured JSON summary.
+	Slack bool
+	// On selects the trigger condition; empty means OnAlways.
+	On string
+}
+
+// summary is the structured payload for plain JSON webhooks.
+type summary struct {
+	Targets   []string   `json:"targets"`
+	Endpoints int        `json:"endpoints"`
+	High      int        `json:"high_confidence"`
+	Medium    int        `json:"medium_confidence"`
+	Low       int        `json:"low_confidence"`
+	Report    string     `json:"report,omitempty"`
+	Finished  types.Time `json:"finished_at"`
+}
+
+// ScanDone sends the completion notification for result. reportPath
+// points readers at the full report and may be empty.
+func (n *Notifier) ScanDone(ctx context.Context, c *client.Client, result *types.ScanResult, reportPath string) error {
+	high, medium, low := 0, 0, 0
+	for _, endpoint := range result.Endpoints {
+		switch {
+		case endpoint.Confidence >= types.ConfidenceHigh:
+			high++
+		case endpoint.Confidence >= types.ConfidenceMedium:
+			medium++
+		default:
+			low++
+		}
+	}
+	if n.On == OnHigh && high == 0 {
+		return nil
+	}
+
+	targets := make([]string, 0, len(result.Targets))
+	for _, target := range result.Targets {
+		targets = append(targets, target.Address())
+	}
+
+	var body []byte
+	var err error
+	if n.Slack {
+		text := fmt.Sprintf("vespasian scan of %s finished: %d endpoints (%d high, %d medium, %d low confidence)",
+			strings.Join(targets, ", "), len(result.Endpoints), high, medium, low)
+		if reportPath != "" {
+			text += " — report: " + reportPath
+		}
+		body, err = json.Marshal(map[string]string{"text": text})
+	} else {
+		body, err = json.Marshal(summary{
+			Targets:   targets,
+			Endpoints: len(result.Endpoints),
+			High:      high,
+			Medium:    medium,
+			Low:       low,
+			Report:    reportPath,
+			Finished:  result.FinishedAt,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Post(ctx, n.URL, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		return fmt.Errorf("notify: webhook POST failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("notify: webhook answered %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package objstore uploads finished reports to object storage, so scans
+// running on ephemeral CI runners can persist their output without a
+// filesystem that outlives them. S3 URIs are signed with AWS Signature
+// Version 4 using the standard environment credentials; GCS URIs use the
+// JSON upload API with an OAuth access token from the environment. No
+// vendor SDK is involved.
+package objstore
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// uploadClient keeps uploads off the probe client: reports go to cloud
+// control planes, not to scan targets.
+var uploadClient = &http.Client{Timeout: 60 * time.Second}
+
+var (
+	sseMu    sync.RWMutex
+	sseValue string
+)
+
+// SetSSE selects S3 server-side encryption for uploads: "AES256" or
+// "aws:kms". Empty leaves the bucket default in force.
+func SetSSE(value string) {
+	sseMu.Lock()
+	sseValue = value
+	sseMu.Unlock()
+}
+
+func sse() string {
+	sseMu.RLock()
+	defer sseMu.RUnlock()
+	return sseValue
+}
+
+// IsURI reports whether path names an object storage location rather
+// than a local file.
+func IsURI(path string) bool {
+	return strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "gs://")
+}
+
+// Upload writes data to the object named by uri.
+func Upload(uri string, data []byte) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(uri, "gs://") {
+		return uploadGCS(bucket, key, data)
+	}
+	return uploadS3(bucket, key, data)
+}
+
+// splitURI separates "scheme://bucket/key/path" into bucket and key.
+func splitURI(uri string) (bucket, key string, err error) {
+	rest := uri[strings.Index(uri, "://")+3:]
+	bucket, key, ok := strings.Cut(rest, "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("objstore: invalid URI %q, want scheme://bucket/key", uri)
+	}
+	return bucket, key, nil
+}
+
+// uploadGCS streams data through the GCS JSON upload API, authenticated
+// by the GOOGLE_OAUTH_ACCESS_TOKEN environment variable.
+func uploadGCS(bucket, key string, data []byte) error {
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		return fmt.Errorf("objstore: GOOGLE_OAUTH_ACCESS_TOKEN not set")
+	}
+	uploadURL := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		url.PathEscape(bucket), url.QueryEscape(key))
+	req, err := http.NewRequest(http.MethodPost, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return send(req, "gs://"+bucket+"/"+key)
+}
+
+// uploadS3 PUTs data with an AWS SigV4 signature from the standard
+// AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY (and optionally
+// AWS_SESSION_TOKEN, AWS_REGION) environment variables.
+func uploadS3(bucket, key string, data []byte) error {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("objstore: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY not set")
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	host := bucket + ".s3." + region + ".amazonaws.com"
+	target := &url.URL{Scheme: "https", Host: host, Path: "/" + key}
+	req, err := http.NewRequest(http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	payloadHash := hexSHA256(data)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("x-amz-security-token", token)
+	}
+	if value := sse(); value != "" {
+		req.Header.Set("x-amz-server-side-encryption", value)
+	}
+
+	// Canonical headers must be lowercase and sorted; collect the ones
+	// that participate in the signature.
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("x-amz-security-token") != "" {
+		signed = append(signed, "x-amz-security-token")
+	}
+	if req.Header.Get("x-amz-server-side-encryption") != "" {
+		signed = append(signed, "x-amz-server-side-encryption")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = host
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPut,
+		target.EscapedPath(),
+		"", // no query string
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := dateStamp + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+	return send(req, "s3://"+bucket+"/"+key)
+}
+
+// send executes an upload request and folds non-2xx answers into errors.
+func send(req *http.Request, uri string) error {
+	resp, err := uploadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("objstore: upload to %s failed: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("objstore: upload to %s answered %d: %s", uri, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
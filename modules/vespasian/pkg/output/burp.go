@@ -0,0 +1,41 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// BurpWriter renders discovered endpoints as a plain URL list Burp Suite
+// (and ZAP) accept as pasted scope or spider seeds, closing the loop
+// between vespasian discovery and interactive proxy work.
+type BurpWriter struct{}
+
+// Write implements Writer.
+func (*BurpWriter) Write(w io.Writer, result *types.ScanResult) error {
+	seen := map[string]bool{}
+	urls := make([]string, 0, len(result.Endpoints))
+	for _, endpoint := range result.Endpoints {
+		// Fragments (GraphQL operation signatures) do not travel on the
+		// wire; strip them so the list holds requestable URLs.
+		u := endpoint.URL
+		if i := strings.Index(u, "#"); i >= 0 {
+			u = u[:i]
+		}
+		if u == "" || !strings.HasPrefix(u, "http") || seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	for _, u := range urls {
+		if _, err := fmt.Fprintln(w, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// CycloneDXWriter renders the inventory as a CycloneDX 1.5 BOM whose
+// services are the discovered hosts — a SaaSBOM — so the scan output can
+// be ingested by SBOM tooling and Dependency-Track style platforms. Each
+// service carries its endpoint URLs, the protocols observed as data
+// classifications, and an authenticated flag when the auth classification
+// pass produced a uniform verdict.
+type CycloneDXWriter struct{}
+
+type cdxBOM struct {
+	BOMFormat   string       `json:"bomFormat"`
+	SpecVersion string       `json:"specVersion"`
+	Version     int          `json:"version"`
+	Metadata    cdxMetadata  `json:"metadata"`
+	Services    []cdxService `json:"services"`
+}
+
+type cdxMetadata struct {
+	Timestamp string    `json:"timestamp"`
+	Tools     []cdxTool `json:"tools"`
+}
+
+type cdxTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cdxService struct {
+	BOMRef        string    `json:"bom-ref"`
+	Name          string    `json:"name"`
+	Endpoints     []string  `json:"endpoints"`
+	Authenticated *bool     `json:"authenticated,omitempty"`
+	Data          []cdxData `json:"data,omitempty"`
+}
+
+type cdxData struct {
+	Flow           string `json:"flow"`
+	Classification string `json:"classification"`
+}
+
+// Write implements Writer.
+func (*CycloneDXWriter) Write(w io.Writer, result *types.ScanResult) error {
+	type serviceAccumulator struct {
+		endpoints []string
+		protocols map[string]bool
+		authed    map[string]bool // distinct auth verdicts seen
+	}
+	services := map[string]*serviceAccumulator{}
+	for _, endpoint := range result.Endpoints {
+		parsed, err := url.Parse(endpoint.URL)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		origin := parsed.Scheme + "://" + parsed.Host
+		service := services[origin]
+		if service == nil {
+			service = &serviceAccumulator{protocols: map[string]bool{}, authed: map[string]bool{}}
+			services[origin] = service
+		}
+		service.endpoints = append(service.endpoints, endpoint.URL)
+		service.protocols[endpoint.Protocol] = true
+		if auth := endpoint.Metadata["auth"]; auth != "" {
+			service.authed[auth] = true
+		}
+	}
+
+	origins := make([]string, 0, len(services))
+	for origin := range services {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+
+	rendered := make([]cdxService, 0, len(origins))
+	for _, origin := range origins {
+		service := services[origin]
+		sort.Strings(service.endpoints)
+		entry := cdxService{
+			BOMRef:    "vespasian:service:" + shortHash(origin),
+			Name:      origin,
+			Endpoints: dedupeStrings(service.endpoints),
+		}
+		protocols := make([]string, 0, len(service.protocols))
+		for protocol := range service.protocols {
+			protocols = append(protocols, protocol)
+		}
+		sort.Strings(protocols)
+		for _, protocol := range protocols {
+			entry.Data = append(entry.Data, cdxData{Flow: "bi-directional", Classification: protocol})
+		}
+		// Only claim an authentication posture when every classified
+		// endpoint agreed; a mixed surface stays unstated.
+		if len(service.authed) == 1 {
+			for verdict := range service.authed {
+				authed := verdict == "required" || verdict == "forbidden"
+				entry.Authenticated = &authed
+			}
+		}
+		rendered = append(rendered, entry)
+	}
+
+	bom := cdxBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: result.FinishedAt.Format(time.RFC3339),
+			Tools:     []cdxTool{{Vendor: "praetorian-inc", Name: "vespasian", Version: "1.0.0"}},
+		},
+		Services: rendered,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bom)
+}
+
+// shortHash gives a service a stable bom-ref across scans.
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// dedupeStrings removes adjacent duplicates from a sorted slice.
+func dedupeStrings(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,18 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// JSONWriter renders the scan result as indented JSON.
+type JSONWriter struct{}
+
+// Write implements Writer.
+func (*JSONWriter) Write(w io.Writer, result *types.ScanResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
@@ -0,0 +1,32 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// GroupedJSONWriter renders the scan result as JSON with endpoints grouped
+// per target host, for consumers that process results host by host.
+type GroupedJSONWriter struct{}
+
+type groupedResult struct {
+	Targets    []types.Target        `json:"targets"`
+	Hosts      []inventory.HostGroup `json:"hosts"`
+	StartedAt  types.Time            `json:"started_at"`
+	FinishedAt types.Time            `json:"finished_at"`
+}
+
+// Write implements Writer.
+func (*GroupedJSONWriter) Write(w io.Writer, result *types.ScanResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(groupedResult{
+		Targets:    result.Targets,
+		Hosts:      inventory.GroupByHost(result.Endpoints),
+		StartedAt:  result.StartedAt,
+		FinishedAt: result.FinishedAt,
+	})
+}
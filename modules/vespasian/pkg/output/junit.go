@@ -0,0 +1,95 @@
+package output
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// JUnitWriter renders the scan as a JUnit XML report so CI systems that
+// already ingest test results can gate on discovery: each risk finding
+// becomes a failed test case and each plain inventory endpoint a passed
+// one, so a pipeline fails the moment a new exposed console or
+// deprecated API version shows up.
+type JUnitWriter struct{}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// Write implements Writer.
+func (*JUnitWriter) Write(w io.Writer, result *types.ScanResult) error {
+	report := junitSuites{Name: "vespasian"}
+	for _, group := range inventory.GroupByHost(result.Endpoints) {
+		host := group.Host
+		if host == "" {
+			host = "unknown"
+		}
+		suite := junitSuite{Name: host}
+		for _, endpoint := range group.Endpoints {
+			method := endpoint.Method
+			if method == "" {
+				method = "GET"
+			}
+			// Method plus path keeps case names stable across scans, so
+			// CI trend views track each endpoint rather than each run.
+			testcase := junitCase{
+				Name:      method + " " + endpointPath(endpoint),
+				Classname: host,
+			}
+			if risk := endpoint.Metadata["risk"]; risk != "" {
+				severity := endpoint.Metadata["severity"]
+				if severity == "" {
+					severity = "info"
+				}
+				testcase.Failure = &junitFailure{
+					Message: risk,
+					Type:    severity,
+					Body:    endpoint.URL,
+				}
+				suite.Failures++
+			}
+			suite.Tests++
+			suite.Cases = append(suite.Cases, testcase)
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
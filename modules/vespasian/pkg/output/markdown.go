@@ -0,0 +1,192 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// MarkdownWriter renders a scan as a report fragment ready to paste into
+// an engagement write-up: severity and probe-coverage summary tables, one
+// section per host with a methods-by-path table and its risk findings,
+// and an error appendix for targets that misbehaved during the scan.
+type MarkdownWriter struct{}
+
+// severityOrder fixes the summary table's row order; severities the
+// rules engine never assigned simply do not appear.
+var severityOrder = []string{"critical", "high", "medium", "low", "info"}
+
+// Write implements Writer.
+func (*MarkdownWriter) Write(w io.Writer, result *types.ScanResult) error {
+	fmt.Fprintf(w, "# API Endpoint Inventory\n\n")
+	fmt.Fprintf(w, "Scanned %d target(s), %d endpoint(s) discovered.\n\n",
+		len(result.Targets), len(result.Endpoints))
+
+	writeSeverityTable(w, result.Endpoints)
+	writeCoverageTable(w, result.Endpoints)
+
+	for _, group := range inventory.GroupByHost(result.Endpoints) {
+		host := group.Host
+		if host == "" {
+			host = "(unparsable URLs)"
+		}
+		fmt.Fprintf(w, "## %s\n\n", host)
+		writePathTable(w, group.Endpoints)
+		writeFindings(w, group.Endpoints)
+	}
+
+	writeErrorAppendix(w, result.Health)
+	return nil
+}
+
+// writeSeverityTable counts findings per severity. Endpoints no rule
+// touched count as info, matching the other report formats.
+func writeSeverityTable(w io.Writer, endpoints []types.Endpoint) {
+	counts := map[string]int{}
+	for _, endpoint := range endpoints {
+		severity := endpoint.Metadata["severity"]
+		if severity == "" {
+			severity = "info"
+		}
+		counts[severity]++
+	}
+	fmt.Fprintf(w, "| Severity | Findings |\n|---|---|\n")
+	for _, severity := range severityOrder {
+		if counts[severity] > 0 {
+			fmt.Fprintf(w, "| %s | %d |\n", severity, counts[severity])
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// writeCoverageTable summarizes which probes produced the inventory, so
+// a thin report is distinguishable from a thin scan.
+func writeCoverageTable(w io.Writer, endpoints []types.Endpoint) {
+	counts := map[string]int{}
+	for _, endpoint := range endpoints {
+		counts[endpoint.Source]++
+	}
+	sources := make([]string, 0, len(counts))
+	for source := range counts {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	fmt.Fprintf(w, "| Probe | Endpoints |\n|---|---|\n")
+	for _, source := range sources {
+		fmt.Fprintf(w, "| %s | %d |\n", source, counts[source])
+	}
+	fmt.Fprintln(w)
+}
+
+// writePathTable renders one row per path with every method recorded
+// against it, collapsing the per-method endpoint records the way a
+// reader thinks about an API surface.
+func writePathTable(w io.Writer, endpoints []types.Endpoint) {
+	methods := map[string]map[string]bool{}
+	sources := map[string]map[string]bool{}
+	for _, endpoint := range endpoints {
+		path := endpointPath(endpoint)
+		method := endpoint.Method
+		if method == "" {
+			method = "GET"
+		}
+		if methods[path] == nil {
+			methods[path] = map[string]bool{}
+			sources[path] = map[string]bool{}
+		}
+		methods[path][method] = true
+		sources[path][endpoint.Source] = true
+	}
+	paths := make([]string, 0, len(methods))
+	for path := range methods {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fmt.Fprintf(w, "| Path | Methods | Sources |\n|---|---|---|\n")
+	for _, path := range paths {
+		fmt.Fprintf(w, "| `%s` | %s | %s |\n",
+			strings.ReplaceAll(path, "|", `\|`),
+			strings.Join(sortedKeys(methods[path]), " "),
+			strings.Join(sortedKeys(sources[path]), ", "))
+	}
+	fmt.Fprintln(w)
+}
+
+// writeFindings lists the host's risk findings under the path table;
+// plain inventory endpoints already appear in the table itself.
+func writeFindings(w io.Writer, endpoints []types.Endpoint) {
+	wrote := false
+	for _, endpoint := range endpoints {
+		risk := endpoint.Metadata["risk"]
+		if risk == "" {
+			continue
+		}
+		if !wrote {
+			fmt.Fprintf(w, "### Findings\n\n")
+			wrote = true
+		}
+		severity := endpoint.Metadata["severity"]
+		if severity == "" {
+			severity = "info"
+		}
+		fmt.Fprintf(w, "- **%s** (%s): `%s`\n", risk, severity, endpoint.URL)
+	}
+	if wrote {
+		fmt.Fprintln(w)
+	}
+}
+
+// writeErrorAppendix reports targets that were unreachable or saw probe
+// failures, so gaps in the inventory are explainable from the report
+// alone.
+func writeErrorAppendix(w io.Writer, health []types.TargetHealth) {
+	wrote := false
+	for _, entry := range health {
+		if entry.Reachable && entry.Errors == 0 {
+			continue
+		}
+		if !wrote {
+			fmt.Fprintf(w, "## Scan errors\n\n| Target | Status | Probes failed |\n|---|---|---|\n")
+			wrote = true
+		}
+		status := "unreachable"
+		if entry.Reachable {
+			status = "up, " + entry.Latency
+		}
+		fmt.Fprintf(w, "| %s | %s | %d/%d |\n", entry.Target, status, entry.Errors, entry.Probes)
+	}
+	if wrote {
+		fmt.Fprintln(w)
+	}
+}
+
+// endpointPath extracts the path (with query) an endpoint answers on,
+// matching the nuclei writer's treatment of fragments.
+func endpointPath(endpoint types.Endpoint) string {
+	u, err := url.Parse(endpoint.URL)
+	if err != nil {
+		return endpoint.URL
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
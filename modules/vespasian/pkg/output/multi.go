@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/praetorian-inc/vespasian/pkg/objstore"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Destination pairs an output format with where it is written; an empty
+// Path means stdout.
+type Destination struct {
+	Format string
+	Path   string
+}
+
+// WriteAll renders the result to every destination, so one scan can
+// produce human and machine output without re-running. Every destination
+// is attempted; the first error is returned.
+func WriteAll(destinations []Destination, stdout io.Writer, result *types.ScanResult) error {
+	var first error
+	for _, destination := range destinations {
+		if err := writeOne(destination, stdout, result); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func writeOne(destination Destination, stdout io.Writer, result *types.ScanResult) error {
+	writer, err := Get(destination.Format)
+	if err != nil {
+		return err
+	}
+	if objstore.IsURI(destination.Path) {
+		// Render in memory and hand the document to the object store;
+		// there may be no useful filesystem on an ephemeral runner.
+		var buf bytes.Buffer
+		if err := writer.Write(&buf, result); err != nil {
+			return fmt.Errorf("%s writer failed: %w", destination.Format, err)
+		}
+		return objstore.Upload(destination.Path, buf.Bytes())
+	}
+	w := stdout
+	if destination.Path != "" {
+		f, err := os.Create(destination.Path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writer.Write(w, result); err != nil {
+		return fmt.Errorf("%s writer failed: %w", destination.Format, err)
+	}
+	return nil
+}
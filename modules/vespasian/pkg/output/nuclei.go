@@ -0,0 +1,110 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// NucleiWriter renders discovered endpoints as nuclei HTTP templates, one
+// template per host with one request block per method, so discovery output
+// can drive follow-on vulnerability scanning directly.
+type NucleiWriter struct{}
+
+// Write implements Writer.
+func (*NucleiWriter) Write(w io.Writer, result *types.ScanResult) error {
+	first := true
+	for _, group := range inventory.GroupByHost(result.Endpoints) {
+		if group.Host == "" {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(w, "---")
+		}
+		first = false
+		if err := writeHostTemplate(w, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHostTemplate(w io.Writer, group inventory.HostGroup) error {
+	// Paths are templated against {{BaseURL}}; fragments (GraphQL
+	// operation signatures) do not travel on the wire and are dropped.
+	byMethod := map[string][]string{}
+	seen := map[string]bool{}
+	for _, endpoint := range group.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil {
+			continue
+		}
+		path := u.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if u.RawQuery != "" {
+			path += "?" + u.RawQuery
+		}
+		method := endpoint.Method
+		if method == "" {
+			method = "GET"
+		}
+		key := method + " " + path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		byMethod[method] = append(byMethod[method], path)
+	}
+
+	methods := make([]string, 0, len(byMethod))
+	for method := range byMethod {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	fmt.Fprintf(w, "id: vespasian-%s\n", templateID(group.Host))
+	fmt.Fprintln(w, "info:")
+	fmt.Fprintf(w, "  name: Discovered API endpoints on %s\n", group.Host)
+	fmt.Fprintln(w, "  author: vespasian")
+	fmt.Fprintln(w, "  severity: info")
+	fmt.Fprintln(w, "  tags: vespasian,api-discovery")
+	fmt.Fprintln(w, "http:")
+	for _, method := range methods {
+		paths := byMethod[method]
+		sort.Strings(paths)
+		fmt.Fprintf(w, "  - method: %s\n", method)
+		fmt.Fprintln(w, "    path:")
+		for _, path := range paths {
+			fmt.Fprintf(w, "      - %q\n", "{{BaseURL}}"+path)
+		}
+		fmt.Fprintln(w, "    matchers:")
+		fmt.Fprintln(w, "      - type: status")
+		fmt.Fprintln(w, "        status:")
+		fmt.Fprintln(w, "          - 200")
+		fmt.Fprintln(w, "          - 401")
+		fmt.Fprintln(w, "          - 403")
+	}
+	return nil
+}
+
+// templateID derives a nuclei-safe template id from a host:port string.
+func templateID(host string) string {
+	id := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, host)
+	return strings.Trim(id, "-")
+}
@@ -0,0 +1,40 @@
+// Package output renders scan results in the formats vespasian supports.
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Writer renders a scan result to w.
+type Writer interface {
+	Write(w io.Writer, result *types.ScanResult) error
+}
+
+// Get returns the writer for the named format.
+func Get(format string) (Writer, error) {
+	switch format {
+	case "json":
+		return &JSONWriter{}, nil
+	case "json-grouped":
+		return &GroupedJSONWriter{}, nil
+	case "terminal":
+		return &TerminalWriter{}, nil
+	case "markdown":
+		return &MarkdownWriter{}, nil
+	case "sarif":
+		return &SARIFWriter{}, nil
+	case "nuclei":
+		return &NucleiWriter{}, nil
+	case "burp":
+		return &BurpWriter{}, nil
+	case "cyclonedx":
+		return &CycloneDXWriter{}, nil
+	case "junit":
+		return &JUnitWriter{}, nil
+	default:
+		return nil, fmt.Errorf("output: unknown format %q", format)
+	}
+}
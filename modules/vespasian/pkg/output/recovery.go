@@ -0,0 +1,39 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// WriteRecovery dumps the scan result as NDJSON to path: one line for the
+// scan frame (targets, timings, health) and one per endpoint. It is the
+// fallback when the chosen writer fails mid-run, so a formatting bug never
+// costs the results of an hours-long scan. NDJSON is written line by line
+// with no document-level structure to get wrong.
+func WriteRecovery(path string, result *types.ScanResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("output: creating recovery file: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	frame := struct {
+		Targets    []types.Target       `json:"targets"`
+		Health     []types.TargetHealth `json:"health,omitempty"`
+		StartedAt  types.Time           `json:"started_at"`
+		FinishedAt types.Time           `json:"finished_at"`
+	}{result.Targets, result.Health, result.StartedAt, result.FinishedAt}
+	if err := enc.Encode(frame); err != nil {
+		return err
+	}
+	for _, endpoint := range result.Endpoints {
+		if err := enc.Encode(endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// SARIFWriter renders the scan result as a SARIF 2.1.0 log so findings
+// slot into code-scanning pipelines. Each probe becomes a rule and each
+// endpoint a result carrying a stable partial fingerprint, letting SARIF
+// consumers deduplicate results across scans.
+type SARIFWriter struct{}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+// ruleDescriptions gives each probe-derived rule a human summary.
+var ruleDescriptions = map[string]string{
+	"openapi":         "Endpoint discovered from an OpenAPI/Swagger specification",
+	"graphql":         "GraphQL operation discovered through schema introspection",
+	"crawler":         "Endpoint discovered by crawling the HTML surface",
+	"upload":          "Upload-capable endpoint identified",
+	"error-verbosity": "Service returns verbose error details",
+}
+
+// Write implements Writer.
+func (*SARIFWriter) Write(w io.Writer, result *types.ScanResult) error {
+	ruleIDs := map[string]bool{}
+	results := make([]sarifResult, 0, len(result.Endpoints))
+	for _, endpoint := range result.Endpoints {
+		ruleID := "vespasian/" + endpoint.Source
+		ruleIDs[ruleID] = true
+
+		r := sarifResult{
+			RuleID: ruleID,
+			Level:  "note",
+			Message: sarifMessage{
+				Text: endpoint.Method + " " + endpoint.URL + " (" + endpoint.Protocol + ")",
+			},
+			PartialFingerprints: map[string]string{
+				"vespasian/endpoint/v1": fingerprint(endpoint),
+			},
+			Properties: endpoint.Metadata,
+		}
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = endpoint.URL
+		r.Locations = []sarifLocation{loc}
+		results = append(results, r)
+	}
+
+	rules := make([]sarifRule, 0, len(ruleIDs))
+	for id := range ruleIDs {
+		name := id[len("vespasian/"):]
+		description := ruleDescriptions[name]
+		if description == "" {
+			description = "Endpoint discovered by the " + name + " probe"
+		}
+		rules = append(rules, sarifRule{
+			ID:               id,
+			Name:             name,
+			ShortDescription: sarifMessage{Text: description},
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "vespasian",
+				InformationURI: "https://github.com/praetorian-inc/vespasian",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// fingerprint returns a stable hash identifying an endpoint independent of
+// scan-specific metadata, so results correlate across runs.
+func fingerprint(endpoint types.Endpoint) string {
+	sum := sha256.Sum256([]byte(endpoint.Method + " " + endpoint.URL + " " + endpoint.Protocol))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,47 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TerminalWriter renders a human-readable inventory, grouping endpoints
+// into path families so large scans stay navigable.
+type TerminalWriter struct{}
+
+// Write implements Writer.
+func (*TerminalWriter) Write(w io.Writer, result *types.ScanResult) error {
+	fmt.Fprintf(w, "Scanned %d target(s), %d endpoint(s) discovered\n",
+		len(result.Targets), len(result.Endpoints))
+
+	for _, health := range result.Health {
+		status := "unreachable"
+		if health.Reachable {
+			status = "up, " + health.Latency
+			if health.TLSHandshake != "" {
+				status += " (tls " + health.TLSHandshake + ")"
+			}
+		}
+		fmt.Fprintf(w, "  %s: %s, %d/%d probes failed\n",
+			health.Target, status, health.Errors, health.Probes)
+	}
+
+	for _, group := range inventory.GroupByHost(result.Endpoints) {
+		fmt.Fprintf(w, "\n%s (%d endpoints)\n", group.Host, len(group.Endpoints))
+		for _, cluster := range inventory.ClusterEndpoints(group.Endpoints) {
+			if len(cluster.Endpoints) > 1 {
+				fmt.Fprintf(w, "  %s — %d endpoints\n", cluster.Pattern, len(cluster.Endpoints))
+				for _, endpoint := range cluster.Endpoints {
+					fmt.Fprintf(w, "    %-7s %s\n", endpoint.Method, endpoint.URL)
+				}
+			} else {
+				endpoint := cluster.Endpoints[0]
+				fmt.Fprintf(w, "  %-7s %s\n", endpoint.Method, endpoint.URL)
+			}
+		}
+	}
+	return nil
+}
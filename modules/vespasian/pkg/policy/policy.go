@@ -0,0 +1,213 @@
+// Package policy rewrites finding severity after discovery based on
+// configured match rules. Without rules every finding reports as plain
+// info; a policy file encodes the engagement's judgment — an admin
+// panel answering 200 is high, an exposed Docker API on a production
+// host is critical — so reports and CI gates rank findings without
+// post-processing. Rules use the same minimal line-oriented syntax as
+// the config file (deliberately not full YAML or a CEL interpreter,
+// which would be vespasian's first dependency): one rule per line,
+// matched in order, last match wins.
+package policy
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// severities are the levels a rule may assign, in escalation order.
+var severities = map[string]bool{
+	"info":     true,
+	"low":      true,
+	"medium":   true,
+	"high":     true,
+	"critical": true,
+}
+
+// Rule assigns a severity (and optionally a risk label) to every
+// endpoint all of its clauses match.
+type Rule struct {
+	// Severity is written to the endpoint's severity metadata.
+	Severity string
+	// Risk, when set, labels the endpoint as a risk finding; this lets a
+	// rule escalate a plain inventory endpoint into the findings section
+	// of reports.
+	Risk string
+	// Clauses must all match for the rule to fire.
+	Clauses []Clause
+}
+
+// Clause matches one endpoint attribute: "field=value" for an exact
+// match or "field~regex" for a pattern match. Fields url, host, path,
+// method, and source address the endpoint record; any other field reads
+// the metadata key of that name ("status" is shorthand for the
+// verified_status key the verification pass records).
+type Clause struct {
+	Field   string
+	Value   string
+	Pattern *regexp.Regexp
+}
+
+// Load reads a policy file: one rule per line, "#" comments, blank
+// lines ignored.
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := Parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("policy: %s:%d: %w", path, i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Parse parses one rule line: a severity, an optional bare-word risk
+// label to assign, and one or more match clauses, e.g.
+//
+//	high exposed-admin path~^/admin status=200
+//	critical risk=exposed-docker-api host~prod
+//
+// The first rule labels matching endpoints as exposed-admin risk
+// findings; the second matches the risk metadata existing probes
+// recorded and only rewrites the severity.
+func Parse(line string) (Rule, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return Rule{}, fmt.Errorf("invalid rule %q, want \"severity [risk] clause...\"", line)
+	}
+	rule := Rule{Severity: fields[0]}
+	if !severities[rule.Severity] {
+		return Rule{}, fmt.Errorf("unknown severity %q", rule.Severity)
+	}
+	for _, field := range fields[1:] {
+		if !strings.ContainsAny(field, "=~") {
+			if rule.Risk != "" {
+				return Rule{}, fmt.Errorf("rule %q names two risk labels", line)
+			}
+			rule.Risk = field
+			continue
+		}
+		clause, err := parseClause(field)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Clauses = append(rule.Clauses, clause)
+	}
+	if len(rule.Clauses) == 0 {
+		return Rule{}, fmt.Errorf("rule %q has no match clauses", line)
+	}
+	return rule, nil
+}
+
+func parseClause(field string) (Clause, error) {
+	if name, pattern, ok := cutBefore(field, '~', '='); ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return Clause{}, fmt.Errorf("invalid pattern in %q: %v", field, err)
+		}
+		return Clause{Field: name, Pattern: compiled}, nil
+	}
+	name, value, ok := strings.Cut(field, "=")
+	if !ok || name == "" {
+		return Clause{}, fmt.Errorf("invalid clause %q, want field=value or field~regex", field)
+	}
+	return Clause{Field: name, Value: value}, nil
+}
+
+// cutBefore splits s at the first occurrence of sep, but only when sep
+// appears before any occurrence of other — so "status=2~3" parses as an
+// exact match, not a pattern.
+func cutBefore(s string, sep, other byte) (string, string, bool) {
+	i := strings.IndexByte(s, sep)
+	if i <= 0 {
+		return "", "", false
+	}
+	if j := strings.IndexByte(s, other); j >= 0 && j < i {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
+}
+
+// Apply runs the rules over the endpoints in order; a later matching
+// rule overrides an earlier one, mirroring how configuration keys
+// override each other.
+func Apply(endpoints []types.Endpoint, rules []Rule) {
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		for _, rule := range rules {
+			if !matches(*endpoint, rule) {
+				continue
+			}
+			if endpoint.Metadata == nil {
+				endpoint.Metadata = map[string]string{}
+			}
+			endpoint.Metadata["severity"] = rule.Severity
+			if rule.Risk != "" {
+				endpoint.Metadata["risk"] = rule.Risk
+			}
+		}
+	}
+}
+
+func matches(endpoint types.Endpoint, rule Rule) bool {
+	for _, clause := range rule.Clauses {
+		value, ok := fieldValue(endpoint, clause.Field)
+		if !ok {
+			return false
+		}
+		if clause.Pattern != nil {
+			if !clause.Pattern.MatchString(value) {
+				return false
+			}
+		} else if value != clause.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue resolves a clause field against the endpoint. A missing
+// attribute fails the clause rather than matching an empty string, so
+// "status=200" never fires on endpoints verification skipped.
+func fieldValue(endpoint types.Endpoint, field string) (string, bool) {
+	switch field {
+	case "url":
+		return endpoint.URL, true
+	case "host":
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || u.Host == "" {
+			return "", false
+		}
+		return u.Host, true
+	case "path":
+		u, err := url.Parse(endpoint.URL)
+		if err != nil {
+			return "", false
+		}
+		return u.Path, true
+	case "method":
+		if endpoint.Method == "" {
+			return "GET", true
+		}
+		return endpoint.Method, true
+	case "source":
+		return endpoint.Source, true
+	case "status":
+		field = "verified_status"
+	}
+	value, ok := endpoint.Metadata[field]
+	return value, ok
+}
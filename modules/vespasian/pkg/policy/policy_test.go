@@ -0,0 +1,78 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/praetorian-inc/vespasian/pkg/policy"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TestParse covers both documented rule shapes: a rule that labels a new
+// risk finding, and a rule that rewrites severity on an existing one.
+func TestParse(t *testing.T) {
+	rule, err := policy.Parse("high exposed-admin path~^/admin status=200")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if rule.Severity != "high" || rule.Risk != "exposed-admin" || len(rule.Clauses) != 2 {
+		t.Errorf("parsed %+v, want severity high, risk exposed-admin, two clauses", rule)
+	}
+
+	rule, err = policy.Parse("critical risk=exposed-docker-api host~prod")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if rule.Risk != "" {
+		t.Errorf("risk=... clause parsed as a risk label assignment: %+v", rule)
+	}
+}
+
+// TestParseErrors asserts malformed rules are rejected at load time
+// rather than silently never firing.
+func TestParseErrors(t *testing.T) {
+	for _, line := range []string{
+		"urgent path~^/admin",       // unknown severity
+		"high exposed-admin",        // no match clauses
+		"high one two path~^/admin", // two risk labels
+		"high path~[invalid",        // bad regex
+	} {
+		if _, err := policy.Parse(line); err == nil {
+			t.Errorf("rule %q parsed without error", line)
+		}
+	}
+}
+
+// TestApply asserts rule ordering (last match wins), the status alias
+// for verified_status, and that clauses on missing metadata never fire.
+func TestApply(t *testing.T) {
+	endpoints := []types.Endpoint{
+		{URL: "http://prod.target.invalid/admin/users", Metadata: map[string]string{"verified_status": "200"}},
+		{URL: "http://prod.target.invalid/health"},
+	}
+	rules := []policy.Rule{
+		mustParse(t, "medium exposed-admin path~^/admin"),
+		mustParse(t, "high path~^/admin status=200"),
+		mustParse(t, "critical status=500"),
+	}
+	policy.Apply(endpoints, rules)
+
+	admin := endpoints[0]
+	if admin.Metadata["severity"] != "high" {
+		t.Errorf("admin severity = %q, want the later rule to win with high", admin.Metadata["severity"])
+	}
+	if admin.Metadata["risk"] != "exposed-admin" {
+		t.Errorf("admin risk = %q, want the earlier rule's label kept", admin.Metadata["risk"])
+	}
+	if severity, ok := endpoints[1].Metadata["severity"]; ok {
+		t.Errorf("unverified endpoint got severity %q from a status clause", severity)
+	}
+}
+
+func mustParse(t *testing.T, line string) policy.Rule {
+	t.Helper()
+	rule, err := policy.Parse(line)
+	if err != nil {
+		t.Fatalf("parsing rule %q: %v", line, err)
+	}
+	return rule
+}
@@ -0,0 +1,154 @@
+package probe
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// DefaultPriority is the stage probes run in unless they implement
+// Prioritized.
+const DefaultPriority = 50
+
+// Prioritized is implemented by probes that should run in a specific
+// stage. Probes sharing a priority run concurrently as one band; bands run
+// in ascending order, so lower-priority values run earlier and their
+// findings are visible to later bands through the Discovery context.
+type Prioritized interface {
+	Priority() int
+}
+
+// PriorityOf returns a probe's stage, DefaultPriority when it does not
+// declare one.
+func PriorityOf(p Probe) int {
+	if prioritized, ok := p.(Prioritized); ok {
+		return prioritized.Priority()
+	}
+	return DefaultPriority
+}
+
+// Categorized lets a probe declare its transport category; the scheduler
+// skips, for example, HTTP probes against targets fingerprinting found no
+// HTTP service on. Probes that do not implement it count as CategoryHTTP.
+type Categorized interface {
+	Category() string
+}
+
+// Transport categories.
+const (
+	CategoryHTTP = "http"
+	CategoryRaw  = "raw"
+)
+
+// CategoryOf returns a probe's transport category, CategoryHTTP when it
+// does not declare one.
+func CategoryOf(p Probe) string {
+	if categorized, ok := p.(Categorized); ok {
+		return categorized.Category()
+	}
+	return CategoryHTTP
+}
+
+// Discovery accumulates what earlier probe stages learned about each
+// target — detected technologies, spec document URLs, confirmed
+// protocols — so later stages can skip or refine their work. Keys are
+// target addresses. Safe for concurrent use.
+type Discovery struct {
+	mu           sync.RWMutex
+	technologies map[string]map[string]bool
+	specURLs     map[string]map[string]bool
+	protocols    map[string]map[string]bool
+	httpAlive    map[string]bool
+}
+
+// AddTechnology records a detected technology for a target.
+func (d *Discovery) AddTechnology(target, technology string) {
+	d.add(&d.technologies, target, technology)
+}
+
+// Technologies lists the technologies detected on a target, sorted.
+func (d *Discovery) Technologies(target string) []string {
+	return d.list(d.technologies, target)
+}
+
+// AddSpecURL records a specification document URL seen on a target.
+func (d *Discovery) AddSpecURL(target, url string) {
+	d.add(&d.specURLs, target, url)
+}
+
+// SpecURLs lists the spec URLs recorded for a target, sorted.
+func (d *Discovery) SpecURLs(target string) []string {
+	return d.list(d.specURLs, target)
+}
+
+// AddProtocol records a protocol confirmed on a target.
+func (d *Discovery) AddProtocol(target, protocol string) {
+	d.add(&d.protocols, target, protocol)
+}
+
+// HasProtocol reports whether a protocol has been confirmed on a target.
+func (d *Discovery) HasProtocol(target, protocol string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.protocols[target][protocol]
+}
+
+// SetHTTP records whether a target answered any HTTP request during
+// fingerprinting.
+func (d *Discovery) SetHTTP(target string, alive bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.httpAlive == nil {
+		d.httpAlive = map[string]bool{}
+	}
+	d.httpAlive[target] = alive
+}
+
+// HTTPDown reports that fingerprinting positively determined no HTTP
+// service answers on the target; an unprobed target is not down.
+func (d *Discovery) HTTPDown(target string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	alive, known := d.httpAlive[target]
+	return known && !alive
+}
+
+func (d *Discovery) add(m *map[string]map[string]bool, target, value string) {
+	if value == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if *m == nil {
+		*m = map[string]map[string]bool{}
+	}
+	if (*m)[target] == nil {
+		(*m)[target] = map[string]bool{}
+	}
+	(*m)[target][value] = true
+}
+
+func (d *Discovery) list(m map[string]map[string]bool, target string) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	values := make([]string, 0, len(m[target]))
+	for value := range m[target] {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+type discoveryKey struct{}
+
+// WithDiscovery attaches d to ctx for probes to consult during Run.
+func WithDiscovery(ctx context.Context, d *Discovery) context.Context {
+	return context.WithValue(ctx, discoveryKey{}, d)
+}
+
+// DiscoveryFrom returns the Discovery attached to ctx, or nil when the
+// scan is not staged.
+func DiscoveryFrom(ctx context.Context) *Discovery {
+	d, _ := ctx.Value(discoveryKey{}).(*Discovery)
+	return d
+}
@@ -0,0 +1,67 @@
+// Package probe defines the Probe interface implemented by every discovery
+// technique and the registry the scanner uses to find them.
+package probe
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Probe is a single discovery technique (OpenAPI, GraphQL introspection,
+// gRPC reflection, ...). Probes must be safe for concurrent use.
+type Probe interface {
+	// Name returns a stable identifier used in output and logs.
+	Name() string
+	// Accepts reports whether the probe should run against the target.
+	Accepts(target types.Target) bool
+	// Run executes the probe and returns any discovered endpoints.
+	Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error)
+}
+
+// Configurable is implemented by probes that accept per-probe options
+// from configuration. Configure is called once during scan setup, before
+// any Run. Implementations reject unknown keys so config typos surface
+// instead of silently running with defaults.
+type Configurable interface {
+	Configure(options map[string]string) error
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Probe{}
+)
+
+// Register adds p to the global registry. It panics on duplicate names,
+// which indicates a programming error.
+func Register(p Probe) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[p.Name()]; ok {
+		panic("probe: duplicate registration of " + p.Name())
+	}
+	registry[p.Name()] = p
+}
+
+// All returns every registered probe sorted by name.
+func All() []Probe {
+	mu.RLock()
+	defer mu.RUnlock()
+	probes := make([]Probe, 0, len(registry))
+	for _, p := range registry {
+		probes = append(probes, p)
+	}
+	sort.Slice(probes, func(i, j int) bool { return probes[i].Name() < probes[j].Name() })
+	return probes
+}
+
+// Get returns the probe registered under name, if any.
+func Get(name string) (Probe, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := registry[name]
+	return p, ok
+}
@@ -0,0 +1,197 @@
+// Package broker detects message brokers — Kafka, AMQP, and MQTT — by
+// speaking the first bytes of each protocol's handshake on its default
+// port. Event-streaming endpoints are part of the modern API surface and
+// invisible to HTTP-only discovery.
+package broker
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// Default broker ports.
+const (
+	kafkaPort = 9092
+	amqpPort  = 5672
+	mqttPort  = 1883
+)
+
+// handshakeTimeout bounds each broker handshake attempt.
+const handshakeTimeout = 5 * time.Second
+
+// Probe detects message brokers through their wire handshakes.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "broker" }
+
+// Category implements probe.Categorized: broker handshakes are raw TCP,
+// so an HTTP-dead target is still worth probing.
+func (*Probe) Category() string { return probe.CategoryRaw }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	switch target.Port {
+	case 0, kafkaPort, amqpPort, mqttPort:
+		return true
+	}
+	return false
+}
+
+// check is one broker handshake: it returns the endpoint protocol and
+// any metadata learned, or ok=false when the service did not answer the
+// handshake.
+type check struct {
+	port      int
+	handshake func(conn net.Conn) (map[string]string, bool)
+	protocol  string
+}
+
+var checks = []check{
+	{kafkaPort, kafkaHandshake, types.ProtocolKafka},
+	{amqpPort, amqpHandshake, types.ProtocolAMQP},
+	{mqttPort, mqttHandshake, types.ProtocolMQTT},
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, _ *client.Client) ([]types.Endpoint, error) {
+	var endpoints []types.Endpoint
+	for _, c := range checks {
+		if target.Port != 0 && target.Port != c.port {
+			continue
+		}
+		address := net.JoinHostPort(target.Host, strconv.Itoa(c.port))
+		metadata, ok := attempt(ctx, address, c.handshake)
+		if !ok {
+			continue
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        c.protocol + "://" + address,
+			Protocol:   c.protocol,
+			Source:     "broker",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+	}
+	return endpoints, nil
+}
+
+// attempt dials address and runs the handshake with a deadline.
+func attempt(ctx context.Context, address string, handshake func(net.Conn) (map[string]string, bool)) (map[string]string, bool) {
+	dialer := net.Dialer{Timeout: handshakeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, false
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(handshakeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+	return handshake(conn)
+}
+
+// kafkaHandshake sends an ApiVersions v0 request — the one request every
+// Kafka version answers — and accepts a response echoing its correlation
+// id.
+func kafkaHandshake(conn net.Conn) (map[string]string, bool) {
+	const correlationID = 0x76657370 // "vesp"
+	// Request header: api_key=18 (ApiVersions), api_version=0,
+	// correlation_id, null client_id.
+	body := make([]byte, 10)
+	binary.BigEndian.PutUint16(body[0:], 18)
+	binary.BigEndian.PutUint32(body[4:], correlationID)
+	binary.BigEndian.PutUint16(body[8:], 0xffff) // client_id = null
+	frame := make([]byte, 4+len(body))
+	binary.BigEndian.PutUint32(frame, uint32(len(body)))
+	copy(frame[4:], body)
+	if _, err := conn.Write(frame); err != nil {
+		return nil, false
+	}
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, false
+	}
+	size := binary.BigEndian.Uint32(header[0:])
+	if size < 6 || binary.BigEndian.Uint32(header[4:]) != correlationID {
+		return nil, false
+	}
+	metadata := map[string]string{"broker": "kafka"}
+	if errorCode := int16(binary.BigEndian.Uint16(header[8:])); errorCode == 0 {
+		// The api_versions array length hints at the broker generation.
+		count := make([]byte, 4)
+		if _, err := io.ReadFull(conn, count); err == nil {
+			metadata["api_count"] = strconv.Itoa(int(binary.BigEndian.Uint32(count)))
+		}
+	}
+	return metadata, true
+}
+
+// amqpHandshake offers the AMQP 0-9-1 protocol header. A 0-9-1 broker
+// answers with a Connection.Start frame; a 1.0-only broker echoes back the
+// header naming the version it insists on. Both identify AMQP.
+func amqpHandshake(conn net.Conn) (map[string]string, bool) {
+	if _, err := conn.Write([]byte{'A', 'M', 'Q', 'P', 0, 0, 9, 1}); err != nil {
+		return nil, false
+	}
+	// Both answers — an echoed protocol header or a Connection.Start
+	// frame — carry at least eight bytes.
+	response := make([]byte, 8)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, false
+	}
+	if string(response[:4]) == "AMQP" {
+		version := "1.0"
+		if response[4] == 0 && response[5] == 0 {
+			version = strconv.Itoa(int(response[5])) + "-" + strconv.Itoa(int(response[6])) + "-" + strconv.Itoa(int(response[7]))
+		}
+		return map[string]string{"broker": "amqp", "version": version}, true
+	}
+	// Frame type 1 on channel 0 is Connection.Start: the 0-9-1 greeting.
+	if response[0] == 1 {
+		return map[string]string{"broker": "amqp", "version": "0-9-1"}, true
+	}
+	return nil, false
+}
+
+// mqttHandshake sends an MQTT 3.1.1 CONNECT and accepts any CONNACK —
+// a "not authorized" return code still identifies the broker.
+func mqttHandshake(conn net.Conn) (map[string]string, bool) {
+	connect := []byte{
+		0x10, 16, // CONNECT, remaining length
+		0, 4, 'M', 'Q', 'T', 'T', // protocol name
+		4,    // protocol level 3.1.1
+		2,    // clean session
+		0, 5, // keepalive 5s
+		0, 4, 'v', 'e', 's', 'p', // client id
+	}
+	if _, err := conn.Write(connect); err != nil {
+		return nil, false
+	}
+	connack := make([]byte, 4)
+	if _, err := io.ReadFull(conn, connack); err != nil {
+		return nil, false
+	}
+	if connack[0] != 0x20 || connack[1] != 2 {
+		return nil, false
+	}
+	metadata := map[string]string{"broker": "mqtt", "protocol_level": "3.1.1"}
+	if connack[3] != 0 {
+		metadata["connect_return"] = strconv.Itoa(int(connack[3]))
+	}
+	return metadata, true
+}
@@ -0,0 +1,208 @@
+// Package containerapi detects container control planes exposed to the
+// network: Docker Engine APIs on their conventional daemon ports and
+// Kubernetes API servers and kubelets. An unauthenticated answer from any
+// of these is remote code execution in practice, so confirmed findings
+// are reported at high confidence with a risk marker and version
+// metadata.
+package containerapi
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// Probe checks for exposed Docker and Kubernetes control planes.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "container-api" }
+
+// Accepts implements probe.Probe: Docker daemon ports plus the Kubernetes
+// API server and kubelet ports.
+func (*Probe) Accepts(target types.Target) bool {
+	switch target.Port {
+	case 2375, 2376, 6443, 8443, 10250:
+		return true
+	}
+	return false
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := baseURL(target)
+	switch target.Port {
+	case 2375, 2376:
+		return checkDocker(ctx, c, base), nil
+	case 10250:
+		return checkKubelet(ctx, c, base), nil
+	default:
+		return checkKubernetes(ctx, c, base), nil
+	}
+}
+
+// dockerVersion is the part of the Docker /version document that
+// identifies the engine.
+type dockerVersion struct {
+	Version    string `json:"Version"`
+	APIVersion string `json:"ApiVersion"`
+	Platform   struct {
+		Name string `json:"Name"`
+	} `json:"Platform"`
+}
+
+// checkDocker confirms a Docker Engine API via /version and, when the
+// daemon answers, checks whether the container list is readable too.
+func checkDocker(ctx context.Context, c *client.Client, base string) []types.Endpoint {
+	resp, err := c.Get(ctx, base+"/version")
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	var version dockerVersion
+	if json.Unmarshal(resp.Body, &version) != nil || version.APIVersion == "" {
+		return nil
+	}
+	metadata := map[string]string{
+		"risk":        "exposed-docker-api",
+		"api_version": version.APIVersion,
+	}
+	if version.Version != "" {
+		metadata["version"] = version.Version
+	}
+	if version.Platform.Name != "" {
+		metadata["platform"] = version.Platform.Name
+	}
+	endpoints := []types.Endpoint{{
+		URL:        base + "/version",
+		Method:     "GET",
+		Protocol:   types.ProtocolHTTP,
+		Source:     "container-api",
+		Confidence: types.ConfidenceHigh,
+		Metadata:   metadata,
+	}}
+
+	resp, err = c.Get(ctx, base+"/containers/json")
+	if err != nil || resp.StatusCode != 200 {
+		return endpoints
+	}
+	var containers []json.RawMessage
+	if json.Unmarshal(resp.Body, &containers) != nil {
+		return endpoints
+	}
+	endpoints = append(endpoints, types.Endpoint{
+		URL:        base + "/containers/json",
+		Method:     "GET",
+		Protocol:   types.ProtocolHTTP,
+		Source:     "container-api",
+		Confidence: types.ConfidenceHigh,
+		Metadata: map[string]string{
+			"risk":       "exposed-docker-api",
+			"containers": strconv.Itoa(len(containers)),
+		},
+	})
+	return endpoints
+}
+
+// kubeVersion is the Kubernetes /version document.
+type kubeVersion struct {
+	Major      string `json:"major"`
+	Minor      string `json:"minor"`
+	GitVersion string `json:"gitVersion"`
+	Platform   string `json:"platform"`
+}
+
+// checkKubernetes confirms an API server via /version and reports whether
+// /api answers without credentials.
+func checkKubernetes(ctx context.Context, c *client.Client, base string) []types.Endpoint {
+	resp, err := c.Get(ctx, base+"/version")
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	var version kubeVersion
+	if json.Unmarshal(resp.Body, &version) != nil || version.GitVersion == "" {
+		return nil
+	}
+	metadata := map[string]string{
+		"risk":        "exposed-kubernetes-api",
+		"git_version": version.GitVersion,
+	}
+	if version.Platform != "" {
+		metadata["platform"] = version.Platform
+	}
+	endpoints := []types.Endpoint{{
+		URL:        base + "/version",
+		Method:     "GET",
+		Protocol:   types.ProtocolHTTP,
+		Source:     "container-api",
+		Confidence: types.ConfidenceHigh,
+		Metadata:   metadata,
+	}}
+
+	resp, err = c.Get(ctx, base+"/api")
+	if err != nil {
+		return endpoints
+	}
+	// 401/403 still proves an API server; anonymous discovery access is
+	// the finding worth flagging separately.
+	if resp.StatusCode == 200 {
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + "/api",
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "container-api",
+			Confidence: types.ConfidenceHigh,
+			Metadata: map[string]string{
+				"risk": "exposed-kubernetes-api",
+				"auth": "open",
+			},
+		})
+	}
+	return endpoints
+}
+
+// checkKubelet confirms a kubelet via its read-only pod listing.
+func checkKubelet(ctx context.Context, c *client.Client, base string) []types.Endpoint {
+	resp, err := c.Get(ctx, base+"/pods")
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	var podList struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if json.Unmarshal(resp.Body, &podList) != nil || podList.Kind != "PodList" {
+		return nil
+	}
+	return []types.Endpoint{{
+		URL:        base + "/pods",
+		Method:     "GET",
+		Protocol:   types.ProtocolHTTP,
+		Source:     "container-api",
+		Confidence: types.ConfidenceHigh,
+		Metadata: map[string]string{
+			"risk": "exposed-kubelet",
+			"pods": strconv.Itoa(len(podList.Items)),
+		},
+	}}
+}
+
+func baseURL(target types.Target) string {
+	if target.Scheme != "" {
+		return target.BaseURL()
+	}
+	// 2375 is the Docker daemon's plaintext port; everything else here
+	// speaks TLS.
+	scheme := "https"
+	if target.Port == 2375 {
+		scheme = "http"
+	}
+	return scheme + "://" + target.Address() + target.BasePath
+}
@@ -0,0 +1,118 @@
+// Package crawl exposes the HTML crawler as a probe so it participates in
+// the standard scan pipeline.
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/crawler"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+var (
+	optMu     sync.RWMutex
+	crawlOpts crawler.Options
+)
+
+// SetOptions installs crawl tuning (worker count, per-host limits,
+// politeness delay) applied to every subsequent crawl.
+func SetOptions(opts crawler.Options) {
+	optMu.Lock()
+	crawlOpts = opts
+	optMu.Unlock()
+}
+
+func options() crawler.Options {
+	optMu.RLock()
+	defer optMu.RUnlock()
+	return crawlOpts
+}
+
+// Probe crawls the target's HTML surface.
+type Probe struct{}
+
+// Configure implements probe.Configurable. Options mirror crawler.Options:
+// max_pages, max_depth, max_retries, error_budget, workers, per_host, and
+// delay (a duration such as "500ms").
+func (*Probe) Configure(options map[string]string) error {
+	optMu.Lock()
+	defer optMu.Unlock()
+	for key, value := range options {
+		switch key {
+		case "delay":
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return fmt.Errorf("crawl: invalid option %s=%q", key, value)
+			}
+			crawlOpts.Delay = d
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("crawl: invalid option %s=%q", key, value)
+		}
+		switch key {
+		case "max_pages":
+			crawlOpts.MaxPages = n
+		case "max_depth":
+			crawlOpts.MaxDepth = n
+		case "max_retries":
+			crawlOpts.MaxRetries = n
+		case "error_budget":
+			crawlOpts.ErrorBudget = n
+		case "workers":
+			crawlOpts.Workers = n
+		case "per_host":
+			crawlOpts.PerHost = n
+		default:
+			return fmt.Errorf("crawl: unknown option %q", key)
+		}
+	}
+	return nil
+}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "crawl" }
+
+// Priority implements probe.Prioritized: the crawler runs ahead of the
+// default band so spec URLs it stumbles on reach the spec parsers.
+func (*Probe) Priority() int { return 20 }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	endpoints, err := crawler.New(c, options()).Crawl(ctx, target.BaseURL())
+	if discovery := probe.DiscoveryFrom(ctx); discovery != nil {
+		for _, endpoint := range endpoints {
+			if looksLikeSpec(endpoint.URL) {
+				discovery.AddSpecURL(target.Address(), endpoint.URL)
+			}
+		}
+	}
+	return endpoints, err
+}
+
+// looksLikeSpec recognizes URLs that are probably OpenAPI/Swagger
+// documents worth handing to the spec parsers.
+func looksLikeSpec(url string) bool {
+	lower := strings.ToLower(url)
+	return strings.Contains(lower, "swagger") ||
+		strings.Contains(lower, "openapi") ||
+		strings.Contains(lower, "api-docs")
+}
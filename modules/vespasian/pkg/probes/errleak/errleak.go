@@ -0,0 +1,185 @@
+// Package errleak harvests strings from server error pages. Stack traces
+// and 500 bodies leak internal hostnames, source file paths, and route
+// hints regardless of the implementation language; the probe provokes a
+// few errors, extracts those strings, and reports them as attribute
+// findings. In-scope hostnames are also emitted as candidate targets so
+// they can be fed back into a follow-up scan.
+package errleak
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// provocations are requests likely to draw an error page.
+var provocations = []struct {
+	method string
+	path   string
+	body   string
+}{
+	{"GET", "/vespasian-err-probe-42", ""},
+	{"GET", "/api/vespasian-err-probe-42", ""},
+	{"POST", "/api", "{"},
+	{"POST", "/", "{"},
+}
+
+var (
+	// hostnameRe matches dotted hostnames; extracted values are filtered
+	// to the target's domain before being reported.
+	hostnameRe = regexp.MustCompile(`[a-z0-9][a-z0-9-]{0,62}(?:\.[a-z0-9][a-z0-9-]{0,62}){1,5}`)
+	// unixPathRe matches source and filesystem paths in stack traces.
+	unixPathRe = regexp.MustCompile(`(?:/(?:home|var|usr|opt|srv|app|etc)(?:/[\w.-]+)+|(?:/[\w.-]+)+\.(?:java|py|rb|go|php|js|ts|cs|ex|rs))`)
+	// windowsPathRe matches Windows paths in .NET stack traces.
+	windowsPathRe = regexp.MustCompile(`[A-Za-z]:\\(?:[\w .-]+\\)*[\w .-]+`)
+	// routeRe matches route hints error frameworks echo back.
+	routeRe = regexp.MustCompile(`(?:GET|POST|PUT|PATCH|DELETE) (/[\w/{}.-]*)|"path"\s*:\s*"(/[\w/{}.-]*)"`)
+)
+
+// maxValues caps each extracted string list in the attribute finding.
+const maxValues = 20
+
+// Probe extracts leaked strings from error pages.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "error-strings" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	hostnames := map[string]bool{}
+	paths := map[string]bool{}
+	routes := map[string]bool{}
+
+	for _, provocation := range provocations {
+		var resp *client.Response
+		var err error
+		if provocation.method == "POST" {
+			resp, err = c.Post(ctx, base+provocation.path, "application/json", strings.NewReader(provocation.body))
+		} else {
+			resp, err = c.Get(ctx, base+provocation.path)
+		}
+		if err != nil || resp.StatusCode < 400 {
+			continue
+		}
+		harvest(string(resp.Body), target.Host, hostnames, paths, routes)
+	}
+
+	var endpoints []types.Endpoint
+	if len(hostnames)+len(paths)+len(routes) > 0 {
+		metadata := map[string]string{}
+		if v := join(hostnames); v != "" {
+			metadata["leaked_hostnames"] = v
+		}
+		if v := join(paths); v != "" {
+			metadata["leaked_paths"] = v
+		}
+		if v := join(routes); v != "" {
+			metadata["leaked_routes"] = v
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + "/",
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "error-strings",
+			Confidence: types.ConfidenceMedium,
+			Metadata:   metadata,
+		})
+	}
+
+	// Route hints are endpoints in their own right, if weak ones.
+	for route := range routes {
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + route,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "error-strings",
+			Confidence: types.ConfidenceLow,
+			Metadata:   map[string]string{"origin": "error page"},
+		})
+	}
+
+	// In-scope hostnames become candidate targets for a follow-up scan.
+	for hostname := range hostnames {
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        "http://" + hostname + "/",
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "error-strings",
+			Confidence: types.ConfidenceLow,
+			Metadata:   map[string]string{"candidate_target": "true", "origin": "error page"},
+		})
+	}
+	return endpoints, nil
+}
+
+// harvest extracts leaked strings from one error body. Hostnames are kept
+// only when they share the target's registrable domain, so third-party
+// noise (schema URLs, vendor domains) stays out of the findings.
+func harvest(body, targetHost string, hostnames, paths, routes map[string]bool) {
+	scope := domainSuffix(targetHost)
+	for _, hostname := range hostnameRe.FindAllString(strings.ToLower(body), -1) {
+		if hostname == targetHost || scope == "" || !strings.HasSuffix(hostname, scope) {
+			continue
+		}
+		hostnames[hostname] = true
+	}
+	for _, path := range unixPathRe.FindAllString(body, -1) {
+		paths[path] = true
+	}
+	for _, path := range windowsPathRe.FindAllString(body, -1) {
+		paths[path] = true
+	}
+	for _, match := range routeRe.FindAllStringSubmatch(body, -1) {
+		route := match[1]
+		if route == "" {
+			route = match[2]
+		}
+		if route != "" && route != "/" {
+			routes[route] = true
+		}
+	}
+}
+
+// domainSuffix approximates the registrable domain as the last two labels
+// of the target host; IP targets yield no scope.
+func domainSuffix(host string) string {
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return ""
+	}
+	if _, err := strconv.Atoi(labels[len(labels)-1]); err == nil {
+		return "" // looks like an IPv4 address
+	}
+	return "." + strings.Join(labels[len(labels)-2:], ".")
+}
+
+// join renders a value set as a sorted, capped, comma-joined list.
+func join(values map[string]bool) string {
+	sorted := make([]string, 0, len(values))
+	for value := range values {
+		sorted = append(sorted, value)
+	}
+	sort.Strings(sorted)
+	if len(sorted) > maxValues {
+		sorted = sorted[:maxValues]
+	}
+	return strings.Join(sorted, ", ")
+}
@@ -0,0 +1,105 @@
+// Package errverbosity locates overly verbose error behavior in GraphQL
+// and REST services. It sends deliberately malformed requests and
+// classifies the responses: stack traces, framework banners, and query
+// suggestions all help an attacker map the implementation, so verbose
+// error surfaces are reported as endpoints in their own right.
+package errverbosity
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/probes/graphql"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// indicators are substrings whose presence in an error body marks it as
+// verbose, mapped to what they disclose.
+var indicators = []struct {
+	pattern  *regexp.Regexp
+	disclose string
+}{
+	{regexp.MustCompile(`(?m)^\s+at [\w.$]+\(`), "java-stack-trace"},
+	{regexp.MustCompile(`Traceback \(most recent call last\)`), "python-traceback"},
+	{regexp.MustCompile(`goroutine \d+ \[`), "go-stack-trace"},
+	{regexp.MustCompile(`(?i)at .*node_modules`), "node-stack-trace"},
+	{regexp.MustCompile(`System\.\w+Exception`), "dotnet-exception"},
+	{regexp.MustCompile(`(?i)ORA-\d{5}`), "oracle-error"},
+	{regexp.MustCompile(`(?i)(syntax error|sqlstate|mysql_)`), "sql-error"},
+	{regexp.MustCompile(`(?i)whitelabel error page`), "spring-boot"},
+	{regexp.MustCompile(`(?i)Did you mean`), "graphql-suggestion"},
+}
+
+// restPaths receive a malformed JSON body to provoke parser errors.
+var restPaths = []string{"/api", "/api/v1", "/"}
+
+// Probe classifies error verbosity.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "error-verbosity" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+
+	// REST: malformed JSON bodies provoke framework error handlers.
+	for _, path := range restPaths {
+		resp, err := c.Post(ctx, base+path, "application/json", strings.NewReader(`{"vespasian":`))
+		if err != nil {
+			continue
+		}
+		if endpoint, ok := classify(base+path, "rest", resp); ok {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	// GraphQL: a syntactically broken query draws the error formatter out.
+	for _, path := range graphql.Paths {
+		resp, err := c.Post(ctx, base+path, "application/json", strings.NewReader(`{"query":"query {"}`))
+		if err != nil {
+			continue
+		}
+		if endpoint, ok := classify(base+path, "graphql", resp); ok {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+// classify inspects an error response body and reports the endpoint when
+// the body discloses implementation detail.
+func classify(url, surface string, resp *client.Response) (types.Endpoint, bool) {
+	for _, indicator := range indicators {
+		if !indicator.pattern.Match(resp.Body) {
+			continue
+		}
+		return types.Endpoint{
+			URL:        url,
+			Method:     "POST",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "error-verbosity",
+			Confidence: types.ConfidenceHigh,
+			Metadata: map[string]string{
+				"surface":    surface,
+				"verbosity":  "verbose",
+				"disclosure": indicator.disclose,
+			},
+		}, true
+	}
+	return types.Endpoint{}, false
+}
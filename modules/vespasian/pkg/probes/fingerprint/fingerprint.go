@@ -0,0 +1,142 @@
+// Package fingerprint identifies the technologies behind a target from
+// disclosure headers and framework-specific paths. Technology tags let
+// report readers reason about the stack and future scans pick probes that
+// fit it.
+package fingerprint
+
+import (
+	"context"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// headerRules map disclosure header values to technology tags.
+var headerRules = []struct {
+	header string
+	match  string
+	tag    string
+}{
+	{"Server", "nginx", "nginx"},
+	{"Server", "apache", "apache"},
+	{"Server", "microsoft-iis", "iis"},
+	{"Server", "envoy", "envoy"},
+	{"Server", "caddy", "caddy"},
+	{"Server", "cloudflare", "cloudflare"},
+	{"Server", "kestrel", "aspnet-core"},
+	{"X-Powered-By", "express", "express"},
+	{"X-Powered-By", "php", "php"},
+	{"X-Powered-By", "asp.net", "aspnet"},
+	{"X-Powered-By", "next.js", "nextjs"},
+	{"X-Generator", "drupal", "drupal"},
+	{"X-Drupal-Cache", "", "drupal"},
+	{"X-Aspnet-Version", "", "aspnet"},
+}
+
+// pathRules are framework-specific paths whose presence identifies the
+// stack. The marker, when set, must appear in the response body to count,
+// filtering out catch-all 200 handlers.
+var pathRules = []struct {
+	path   string
+	marker string
+	tag    string
+}{
+	{"/actuator/health", `"status"`, "spring-boot"},
+	{"/wp-json/", `"namespaces"`, "wordpress"},
+	{"/admin/login/", "csrfmiddlewaretoken", "django"},
+	{"/_next/static/", "", "nextjs"},
+	{"/rails/info/routes", "", "rails"},
+}
+
+// Probe fingerprints the technology stack behind a target.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "fingerprint" }
+
+// Priority implements probe.Prioritized: fingerprinting runs in an early
+// band so later probes can consult the detected technologies.
+func (*Probe) Priority() int { return 10 }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	seen := map[string]bool{}
+	var tags []string
+	add := func(tag string) {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	alive := false
+	var endpoints []types.Endpoint
+	if resp, err := c.Get(ctx, base+"/"); err == nil {
+		alive = true
+		for _, rule := range headerRules {
+			value := resp.Header.Get(rule.header)
+			if value == "" {
+				continue
+			}
+			if rule.match == "" || strings.Contains(strings.ToLower(value), rule.match) {
+				add(rule.tag)
+			}
+		}
+	}
+
+	for _, rule := range pathRules {
+		resp, err := c.Get(ctx, base+rule.path)
+		if err != nil {
+			continue
+		}
+		alive = true
+		if resp.StatusCode != 200 {
+			continue
+		}
+		if rule.marker != "" && !strings.Contains(string(resp.Body), rule.marker) {
+			continue
+		}
+		add(rule.tag)
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + rule.path,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "fingerprint",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   map[string]string{"technology": rule.tag},
+		})
+	}
+
+	if discovery := probe.DiscoveryFrom(ctx); discovery != nil {
+		discovery.SetHTTP(target.Address(), alive)
+		for _, tag := range tags {
+			discovery.AddTechnology(target.Address(), tag)
+		}
+	}
+
+	if len(tags) > 0 {
+		// One attribute finding per target carries the full tag set.
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + "/",
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "fingerprint",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   map[string]string{"technologies": strings.Join(tags, ", ")},
+		})
+	}
+	return endpoints, nil
+}
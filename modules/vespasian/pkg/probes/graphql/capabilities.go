@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+// detectCapabilities checks transport features that widen the GraphQL
+// attack surface beyond the schema itself: batched query execution lets
+// an attacker amortize rate limits and brute-force in a single request,
+// and APQ persisted queries add a hash-keyed cache that can be primed
+// with attacker-chosen operations. Each supported feature becomes a
+// metadata entry for the service's attribute finding.
+func detectCapabilities(ctx context.Context, c *client.Client, url string) map[string]string {
+	capabilities := map[string]string{}
+	if detectBatching(ctx, c, url) {
+		capabilities["batching"] = "supported"
+	}
+	if status := detectPersistedQueries(ctx, c, url); status != "" {
+		capabilities["persisted_queries"] = status
+	}
+	return capabilities
+}
+
+// detectBatching sends an array payload of two trivial queries. Servers
+// with batching enabled (apollo-server, graphql-yoga, and most gateways)
+// answer with an array of execution results; everything else answers
+// with a single envelope or an error object.
+func detectBatching(ctx context.Context, c *client.Client, url string) bool {
+	const batch = `[{"query":"query { __typename }"},{"query":"query { __typename }"}]`
+	resp, err := c.Post(ctx, url, "application/json", strings.NewReader(batch))
+	if err != nil || resp.StatusCode >= 400 {
+		return false
+	}
+	var results []map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Body, &results); err != nil || len(results) != 2 {
+		return false
+	}
+	for _, result := range results {
+		_, hasData := result["data"]
+		_, hasErrors := result["errors"]
+		if !hasData && !hasErrors {
+			return false
+		}
+	}
+	return true
+}
+
+// detectPersistedQueries speaks the APQ protocol: a request carrying only
+// a sha256Hash extension for a query the server has never seen. Servers
+// implementing APQ answer PERSISTED_QUERY_NOT_FOUND (inviting the client
+// to register it), which proves the cache is active; an explicit
+// PersistedQueryNotSupported error rules it out. The probe query hashes
+// to a value no client will have registered, so this never executes
+// anything.
+func detectPersistedQueries(ctx context.Context, c *client.Client, url string) string {
+	hash := sha256.Sum256([]byte("query { __vespasianApqProbe }"))
+	payload := `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` +
+		hex.EncodeToString(hash[:]) + `"}}}`
+	resp, err := c.Post(ctx, url, "application/json", strings.NewReader(payload))
+	if err != nil {
+		return ""
+	}
+	var envelope struct {
+		Errors []struct {
+			Message    string `json:"message"`
+			Extensions struct {
+				Code string `json:"code"`
+			} `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		return ""
+	}
+	for _, e := range envelope.Errors {
+		switch {
+		case e.Extensions.Code == "PERSISTED_QUERY_NOT_FOUND",
+			strings.Contains(e.Message, "PersistedQueryNotFound"):
+			return "supported"
+		case e.Extensions.Code == "PERSISTED_QUERY_NOT_SUPPORTED",
+			strings.Contains(e.Message, "PersistedQueryNotSupported"):
+			return ""
+		}
+	}
+	return ""
+}
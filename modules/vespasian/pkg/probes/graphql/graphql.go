@@ -0,0 +1,307 @@
+// Package graphql discovers GraphQL services at their conventional paths
+// and enumerates root operations through schema introspection.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// Paths are the locations GraphQL services are commonly served from.
+var Paths = knowledge.GraphQLPaths
+
+// introspectionQuery asks for root operation types and every type's
+// fields with their arguments; that is enough to enumerate queries and
+// mutations with full signatures.
+const introspectionQuery = `{"query":"query { __schema { queryType { name } mutationType { name } subscriptionType { name } types { name kind fields { name args { name type { name kind ofType { name } } } } } } }"}`
+
+// Probe performs GraphQL endpoint discovery and introspection.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "graphql" }
+
+// Configure implements probe.Configurable. The single option "paths" is a
+// comma-separated list replacing the conventional GraphQL locations.
+func (*Probe) Configure(options map[string]string) error {
+	for key, value := range options {
+		switch key {
+		case "paths":
+			var paths []string
+			for _, path := range strings.Split(value, ",") {
+				if path = strings.TrimSpace(path); path != "" {
+					paths = append(paths, path)
+				}
+			}
+			if len(paths) == 0 {
+				return fmt.Errorf("graphql: invalid option %s=%q", key, value)
+			}
+			Paths = paths
+		default:
+			return fmt.Errorf("graphql: unknown option %q", key)
+		}
+	}
+	return nil
+}
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, path := range Paths {
+		resp, err := c.Post(ctx, base+path, "application/json", strings.NewReader(introspectionQuery))
+		if err != nil || resp.StatusCode >= 500 {
+			continue
+		}
+		operations, err := parseIntrospection(resp.Body)
+		if err != nil && !looksLikeGraphQL(resp.Body) {
+			continue
+		}
+		if len(operations) == 0 {
+			// Introspection disabled or filtered: fall back to field
+			// suggestion abuse against the same endpoint.
+			if !looksLikeGraphQL(resp.Body) {
+				continue
+			}
+			operations = suggestOperations(ctx, c, base+path)
+			if len(operations) == 0 {
+				continue
+			}
+		}
+		// Introspection succeeded (suggestion-recovered operations are
+		// marked): reconstruct the schema as SDL when export is enabled.
+		sdlFile := ""
+		if exportDir() != "" && !operations[0].suggested {
+			file, err := exportSDL(ctx, c, base+path)
+			if err != nil {
+				log.Printf("graphql: sdl export for %s failed: %v", base+path, err)
+			} else {
+				sdlFile = file
+			}
+		}
+
+		// Subscriptions run over a separate transport; detect it once
+		// per service and annotate every subscription operation.
+		transport := ""
+		for _, op := range operations {
+			if op.kind == "subscription" {
+				transport = detectSubscriptionTransport(ctx, c, base+path)
+				break
+			}
+		}
+
+		// One attribute finding per service carries the transport
+		// capabilities (batching, persisted queries) when any are on.
+		if capabilities := detectCapabilities(ctx, c, base+path); len(capabilities) > 0 {
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        base + path,
+				Method:     "POST",
+				Protocol:   types.ProtocolGraphQL,
+				Source:     "graphql",
+				Confidence: types.ConfidenceHigh,
+				Metadata:   capabilities,
+			})
+		}
+
+		for _, op := range operations {
+			confidence := types.ConfidenceHigh
+			metadata := map[string]string{
+				"operation": op.kind,
+				"field":     op.name,
+			}
+			if op.kind == "subscription" && transport != "" {
+				metadata["subscription_transport"] = transport
+			}
+			if op.suggested {
+				confidence = types.ConfidenceLow
+				metadata["origin"] = "field-suggestion"
+			}
+			if len(op.args) > 0 {
+				metadata["args"] = strings.Join(op.args, ", ")
+			}
+			if sdlFile != "" {
+				metadata["sdl_file"] = sdlFile
+			}
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        base + path + "#" + op.path(),
+				Method:     "POST",
+				Protocol:   types.ProtocolGraphQL,
+				Source:     "graphql",
+				Confidence: confidence,
+				Metadata:   metadata,
+			})
+		}
+		// One service per host is the overwhelmingly common case; stop
+		// at the first path that introspects successfully.
+		break
+	}
+	return endpoints, nil
+}
+
+type operation struct {
+	kind      string // "query", "mutation", or "subscription"
+	name      string
+	args      []string // "name: Type" argument signatures
+	suggested bool     // recovered via field suggestions, not introspection
+}
+
+// path renders the operation as a distinguishable endpoint path fragment:
+// query.user(id: ID) rather than a bare /graphql shared by everything.
+func (op operation) path() string {
+	if len(op.args) == 0 {
+		return op.kind + "." + op.name
+	}
+	return op.kind + "." + op.name + "(" + strings.Join(op.args, ", ") + ")"
+}
+
+// looksLikeGraphQL reports whether a response body is a GraphQL execution
+// result: servers with introspection disabled still answer in the
+// {"data": ...} / {"errors": ...} envelope.
+func looksLikeGraphQL(body []byte) bool {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return false
+	}
+	_, hasData := envelope["data"]
+	_, hasErrors := envelope["errors"]
+	return hasData || hasErrors
+}
+
+// introspectionResponse is the subset of the introspection result the
+// probe reads.
+type introspectionResponse struct {
+	Data struct {
+		Schema struct {
+			QueryType        *struct{ Name string } `json:"queryType"`
+			MutationType     *struct{ Name string } `json:"mutationType"`
+			SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+			Types            []struct {
+				Name   string `json:"name"`
+				Kind   string `json:"kind"`
+				Fields []struct {
+					Name string `json:"name"`
+					Args []struct {
+						Name string  `json:"name"`
+						Type typeRef `json:"type"`
+					} `json:"args"`
+				} `json:"fields"`
+			} `json:"types"`
+		} `json:"__schema"`
+	} `json:"data"`
+}
+
+// typeRef is an introspection type reference, unwrapped one level for
+// NON_NULL and LIST wrappers.
+type typeRef struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	OfType *struct {
+		Name string `json:"name"`
+	} `json:"ofType"`
+}
+
+// render gives a readable type name for an argument signature.
+func (t typeRef) render() string {
+	name := t.Name
+	if name == "" && t.OfType != nil {
+		name = t.OfType.Name
+	}
+	if name == "" {
+		return strings.ToLower(t.Kind)
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return name + "!"
+	case "LIST":
+		return "[" + name + "]"
+	}
+	return name
+}
+
+func parseIntrospection(body []byte) ([]operation, error) {
+	var doc introspectionResponse
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+	schema := doc.Data.Schema
+	rootKinds := map[string]string{}
+	if schema.QueryType != nil {
+		rootKinds[schema.QueryType.Name] = "query"
+	}
+	if schema.MutationType != nil {
+		rootKinds[schema.MutationType.Name] = "mutation"
+	}
+	if schema.SubscriptionType != nil {
+		rootKinds[schema.SubscriptionType.Name] = "subscription"
+	}
+	var operations []operation
+	for _, t := range schema.Types {
+		kind, ok := rootKinds[t.Name]
+		if !ok {
+			continue
+		}
+		for _, field := range t.Fields {
+			op := operation{kind: kind, name: field.Name}
+			for _, arg := range field.Args {
+				op.args = append(op.args, arg.Name+": "+arg.Type.render())
+			}
+			operations = append(operations, op)
+		}
+	}
+	return operations, nil
+}
+
+// detectSubscriptionTransport probes which transport the service exposes
+// for subscriptions: the graphql-ws WebSocket subprotocols or SSE.
+func detectSubscriptionTransport(ctx context.Context, c *client.Client, url string) string {
+	// WebSocket: a proper upgrade needs a raw socket, but servers that
+	// speak graphql-ws answer an upgrade attempt with 101 or, without
+	// the full handshake, 400/426 rather than 404/405.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err == nil {
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dmVzcGFzaWFuLXByb2JlLg==")
+		req.Header.Set("Sec-WebSocket-Protocol", "graphql-transport-ws, graphql-ws")
+		if resp, err := c.Do(req); err == nil {
+			switch resp.StatusCode {
+			case http.StatusSwitchingProtocols:
+				return "websocket"
+			case http.StatusBadRequest, http.StatusUpgradeRequired:
+				if resp.Header.Get("Sec-WebSocket-Protocol") != "" || resp.Header.Get("Sec-WebSocket-Version") != "" {
+					return "websocket"
+				}
+			}
+		}
+	}
+
+	// SSE: graphql-sse services answer event-stream accepts in kind.
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err == nil {
+		req.Header.Set("Accept", "text/event-stream")
+		if resp, err := c.Do(req); err == nil && resp.StatusCode == http.StatusOK &&
+			strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+			return "sse"
+		}
+	}
+	return ""
+}
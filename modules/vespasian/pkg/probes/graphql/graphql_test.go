@@ -0,0 +1,51 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseIntrospection asserts root operation fields come out of an
+// introspection result with their argument signatures rendered, and
+// non-root types are ignored.
+func TestParseIntrospection(t *testing.T) {
+	body := []byte(`{"data":{"__schema":{
+		"queryType":{"name":"Query"},
+		"mutationType":{"name":"Mutation"},
+		"types":[
+			{"name":"Query","kind":"OBJECT","fields":[
+				{"name":"user","args":[{"name":"id","type":{"kind":"NON_NULL","ofType":{"name":"ID"}}}]},
+				{"name":"orders","args":[]}
+			]},
+			{"name":"Mutation","kind":"OBJECT","fields":[
+				{"name":"createOrder","args":[{"name":"input","type":{"name":"OrderInput","kind":"INPUT_OBJECT"}}]}
+			]},
+			{"name":"Order","kind":"OBJECT","fields":[{"name":"id","args":[]}]}
+		]}}}`)
+
+	operations, err := parseIntrospection(body)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	paths := make([]string, len(operations))
+	for i, op := range operations {
+		paths[i] = op.path()
+	}
+	want := []string{"query.user(id: ID!)", "query.orders", "mutation.createOrder(input: OrderInput)"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("operations = %v, want %v", paths, want)
+	}
+}
+
+// TestParseSuggestions asserts field names are pulled from "Did you mean"
+// errors without picking up the quoted guess that provoked them.
+func TestParseSuggestions(t *testing.T) {
+	body := []byte(`{"errors":[{"message":"Cannot query field \"userZq\" on type \"Query\". Did you mean \"user\", \"users\", or \"orders\"?"}]}`)
+	names := parseSuggestions(body)
+	if want := []string{"user", "users", "orders"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("suggestions = %v, want %v", names, want)
+	}
+	if names := parseSuggestions([]byte(`{"errors":[{"message":"Syntax Error"}]}`)); names != nil {
+		t.Errorf("non-suggestion error yielded names %v", names)
+	}
+}
@@ -0,0 +1,270 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+var (
+	schemaMu  sync.RWMutex
+	schemaDir string
+)
+
+// SetSchemaDir enables SDL export: when introspection succeeds, the full
+// schema is reconstructed and written under dir, one file per service,
+// ready for tools like graphql-voyager. Empty disables the export.
+func SetSchemaDir(dir string) {
+	schemaMu.Lock()
+	schemaDir = dir
+	schemaMu.Unlock()
+}
+
+func exportDir() string {
+	schemaMu.RLock()
+	defer schemaMu.RUnlock()
+	return schemaDir
+}
+
+// typeRefDepth is how deep NON_NULL/LIST wrappers are followed; past
+// seven levels real schemas do not go.
+const typeRefDepth = 7
+
+// fullIntrospectionQuery asks for everything SDL reconstruction needs:
+// every type with fields, arguments, input fields, enum values, union
+// members, and interfaces.
+var fullIntrospectionQuery = buildFullQuery()
+
+func buildFullQuery() string {
+	ref := "name kind"
+	for i := 0; i < typeRefDepth; i++ {
+		ref = "name kind ofType { " + ref + " }"
+	}
+	q := "query { __schema { " +
+		"queryType { name } mutationType { name } subscriptionType { name } " +
+		"types { kind name " +
+		"fields(includeDeprecated: true) { name args { name defaultValue type { " + ref + " } } type { " + ref + " } } " +
+		"inputFields { name defaultValue type { " + ref + " } } " +
+		"interfaces { name } " +
+		"enumValues(includeDeprecated: true) { name } " +
+		"possibleTypes { name } } } }"
+	body, _ := json.Marshal(map[string]string{"query": q})
+	return string(body)
+}
+
+// fullTypeRef is a recursive introspection type reference.
+type fullTypeRef struct {
+	Name   string       `json:"name"`
+	Kind   string       `json:"kind"`
+	OfType *fullTypeRef `json:"ofType"`
+}
+
+// render writes the reference in SDL notation ([Type!]! and friends).
+func (t *fullTypeRef) render() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.render() + "!"
+	case "LIST":
+		return "[" + t.OfType.render() + "]"
+	}
+	return t.Name
+}
+
+type fullIntrospection struct {
+	Data struct {
+		Schema fullSchema `json:"__schema"`
+	} `json:"data"`
+}
+
+type fullSchema struct {
+	QueryType        *struct{ Name string } `json:"queryType"`
+	MutationType     *struct{ Name string } `json:"mutationType"`
+	SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+	Types            []fullType             `json:"types"`
+}
+
+type fullType struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Fields []struct {
+		Name string       `json:"name"`
+		Args []inputValue `json:"args"`
+		Type *fullTypeRef `json:"type"`
+	} `json:"fields"`
+	InputFields   []inputValue            `json:"inputFields"`
+	Interfaces    []struct{ Name string } `json:"interfaces"`
+	EnumValues    []struct{ Name string } `json:"enumValues"`
+	PossibleTypes []struct{ Name string } `json:"possibleTypes"`
+}
+
+type inputValue struct {
+	Name         string       `json:"name"`
+	DefaultValue *string      `json:"defaultValue"`
+	Type         *fullTypeRef `json:"type"`
+}
+
+// builtinScalars ship with every implementation and are left out of the
+// exported SDL, as are the introspection meta types.
+var builtinScalars = map[string]bool{
+	"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true,
+}
+
+// exportSDL re-introspects url with the full query, renders the schema
+// as SDL, and writes it into the export directory. The filename is
+// derived from the service URL so one scan of many targets keeps each
+// schema apart.
+func exportSDL(ctx context.Context, c *client.Client, url string) (string, error) {
+	resp, err := c.Post(ctx, url, "application/json", strings.NewReader(fullIntrospectionQuery))
+	if err != nil {
+		return "", err
+	}
+	var doc fullIntrospection
+	if err := json.Unmarshal(resp.Body, &doc); err != nil {
+		return "", err
+	}
+	schema := doc.Data.Schema
+	if len(schema.Types) == 0 {
+		return "", fmt.Errorf("graphql: empty introspection result from %s", url)
+	}
+	sdl := renderSDL(&schema)
+
+	dir := exportDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, schemaFilename(url))
+	if err := os.WriteFile(path, []byte(sdl), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func renderSDL(schema *fullSchema) string {
+	var b strings.Builder
+
+	// The schema block is only needed when root types use non-default names.
+	roots := []struct{ op, name, fallback string }{}
+	if schema.QueryType != nil {
+		roots = append(roots, struct{ op, name, fallback string }{"query", schema.QueryType.Name, "Query"})
+	}
+	if schema.MutationType != nil {
+		roots = append(roots, struct{ op, name, fallback string }{"mutation", schema.MutationType.Name, "Mutation"})
+	}
+	if schema.SubscriptionType != nil {
+		roots = append(roots, struct{ op, name, fallback string }{"subscription", schema.SubscriptionType.Name, "Subscription"})
+	}
+	custom := false
+	for _, root := range roots {
+		if root.name != root.fallback {
+			custom = true
+		}
+	}
+	if custom {
+		b.WriteString("schema {\n")
+		for _, root := range roots {
+			fmt.Fprintf(&b, "  %s: %s\n", root.op, root.name)
+		}
+		b.WriteString("}\n\n")
+	}
+
+	types := make([]fullType, 0, len(schema.Types))
+	for _, t := range schema.Types {
+		if strings.HasPrefix(t.Name, "__") || (t.Kind == "SCALAR" && builtinScalars[t.Name]) {
+			continue
+		}
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+
+	for i, t := range types {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		renderType(&b, t)
+	}
+	return b.String()
+}
+
+func renderType(b *strings.Builder, t fullType) {
+	switch t.Kind {
+	case "SCALAR":
+		fmt.Fprintf(b, "scalar %s\n", t.Name)
+	case "UNION":
+		members := make([]string, 0, len(t.PossibleTypes))
+		for _, member := range t.PossibleTypes {
+			members = append(members, member.Name)
+		}
+		fmt.Fprintf(b, "union %s = %s\n", t.Name, strings.Join(members, " | "))
+	case "ENUM":
+		fmt.Fprintf(b, "enum %s {\n", t.Name)
+		for _, value := range t.EnumValues {
+			fmt.Fprintf(b, "  %s\n", value.Name)
+		}
+		b.WriteString("}\n")
+	case "INPUT_OBJECT":
+		fmt.Fprintf(b, "input %s {\n", t.Name)
+		for _, field := range t.InputFields {
+			fmt.Fprintf(b, "  %s\n", renderInputValue(field))
+		}
+		b.WriteString("}\n")
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if t.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		fmt.Fprintf(b, "%s %s", keyword, t.Name)
+		if len(t.Interfaces) > 0 {
+			names := make([]string, 0, len(t.Interfaces))
+			for _, iface := range t.Interfaces {
+				names = append(names, iface.Name)
+			}
+			fmt.Fprintf(b, " implements %s", strings.Join(names, " & "))
+		}
+		b.WriteString(" {\n")
+		for _, field := range t.Fields {
+			args := ""
+			if len(field.Args) > 0 {
+				rendered := make([]string, 0, len(field.Args))
+				for _, arg := range field.Args {
+					rendered = append(rendered, renderInputValue(arg))
+				}
+				args = "(" + strings.Join(rendered, ", ") + ")"
+			}
+			fmt.Fprintf(b, "  %s%s: %s\n", field.Name, args, field.Type.render())
+		}
+		b.WriteString("}\n")
+	}
+}
+
+func renderInputValue(value inputValue) string {
+	rendered := value.Name + ": " + value.Type.render()
+	if value.DefaultValue != nil {
+		rendered += " = " + *value.DefaultValue
+	}
+	return rendered
+}
+
+// schemaFilename turns a service URL into a safe, distinct filename.
+func schemaFilename(url string) string {
+	name := strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String() + ".graphql"
+}
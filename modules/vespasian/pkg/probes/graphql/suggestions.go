@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+// suggestionSeeds are near-miss field names sent to introspection-disabled
+// servers. graphql-js and most server implementations helpfully answer
+// unknown fields with "Did you mean ...?" suggestions drawn from the real
+// schema, which leaks field names one edit away from the guess.
+var suggestionSeeds = []string{
+	"user", "users", "account", "accounts", "customer", "customers",
+	"order", "orders", "product", "products", "item", "items",
+	"search", "me", "viewer", "node", "nodes", "payment", "invoice",
+	"admin", "setting", "settings", "profile", "organization",
+}
+
+// suggestionRe pulls the quoted field names out of a "Did you mean"
+// error message.
+var suggestionRe = regexp.MustCompile(`"([A-Za-z_][A-Za-z0-9_]*)"`)
+
+// suggestOperations harvests field names from error suggestions. Every
+// seed is sent mangled (with a suffix no schema should contain) so the
+// server always reports an unknown field, and its suggestions reveal the
+// nearby real fields.
+func suggestOperations(ctx context.Context, c *client.Client, url string) []operation {
+	found := map[string]bool{}
+	for _, seed := range suggestionSeeds {
+		query := fmt.Sprintf(`{"query":"query { %sZq }"}`, seed)
+		resp, err := c.Post(ctx, url, "application/json", strings.NewReader(query))
+		if err != nil {
+			continue
+		}
+		for _, name := range parseSuggestions(resp.Body) {
+			found[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	operations := make([]operation, 0, len(names))
+	for _, name := range names {
+		operations = append(operations, operation{kind: "query", name: name, suggested: true})
+	}
+	return operations
+}
+
+// parseSuggestions extracts suggested field names from the errors array of
+// a GraphQL response.
+func parseSuggestions(body []byte) []string {
+	var envelope struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range envelope.Errors {
+		message := e.Message
+		i := strings.Index(message, "Did you mean")
+		if i < 0 {
+			continue
+		}
+		for _, m := range suggestionRe.FindAllStringSubmatch(message[i:], -1) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,79 @@
+// Package grpc enumerates gRPC methods. Servers with reflection disabled
+// are common in production; for those, operators supply the .proto files
+// and the probe ingests them to produce the method inventory.
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+var (
+	methodsMu     sync.RWMutex
+	loadedMethods []Method
+)
+
+func setMethods(methods []Method) {
+	methodsMu.Lock()
+	loadedMethods = methods
+	methodsMu.Unlock()
+}
+
+func currentMethods() []Method {
+	methodsMu.RLock()
+	defer methodsMu.RUnlock()
+	return loadedMethods
+}
+
+// Probe maps proto-derived methods onto gRPC targets.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "grpc" }
+
+// Accepts implements probe.Probe. Conventional gRPC ports plus TLS 443.
+func (*Probe) Accepts(target types.Target) bool {
+	switch target.Port {
+	case 443, 50051, 9090, 8443:
+		return true
+	}
+	return false
+}
+
+// Run implements probe.Probe. Without reflection support there is nothing
+// to ask the server, so the probe emits one endpoint per ingested method.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	methods := currentMethods()
+	if len(methods) == 0 {
+		return nil, nil
+	}
+	base := target.BaseURL()
+
+	endpoints := make([]types.Endpoint, 0, len(methods))
+	for _, method := range methods {
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + method.FullPath(),
+			Method:     "POST",
+			Protocol:   types.ProtocolGRPC,
+			Source:     "grpc",
+			Confidence: types.ConfidenceMedium,
+			Metadata: map[string]string{
+				"service":          method.Service,
+				"rpc":              method.Name,
+				"client_streaming": strconv.FormatBool(method.ClientStreaming),
+				"server_streaming": strconv.FormatBool(method.ServerStreaming),
+				"origin":           "proto-ingestion",
+			},
+		})
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Method is one RPC parsed from a .proto file, addressed the way gRPC
+// frames it on the wire: /package.Service/Method.
+type Method struct {
+	Service         string // fully qualified service name
+	Name            string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// FullPath returns the gRPC request path for the method.
+func (m Method) FullPath() string {
+	return "/" + m.Service + "/" + m.Name
+}
+
+var (
+	packageRe = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)\s*;`)
+	serviceRe = regexp.MustCompile(`(?m)^\s*service\s+(\w+)\s*\{`)
+	rpcRe     = regexp.MustCompile(`rpc\s+(\w+)\s*\(\s*(stream\s+)?[\w.]+\s*\)\s*returns\s*\(\s*(stream\s+)?[\w.]+\s*\)`)
+	commentRe = regexp.MustCompile(`//[^\n]*`)
+)
+
+// ParseProto extracts service methods from proto source. It is a
+// lightweight syntactic pass — enough to enumerate methods on servers
+// that do not expose reflection — not a full protobuf compiler.
+func ParseProto(source []byte) []Method {
+	text := commentRe.ReplaceAllString(string(source), "")
+
+	pkg := ""
+	if m := packageRe.FindStringSubmatch(text); m != nil {
+		pkg = m[1]
+	}
+
+	var methods []Method
+	for _, loc := range serviceRe.FindAllStringSubmatchIndex(text, -1) {
+		name := text[loc[2]:loc[3]]
+		body := braceBody(text[loc[1]-1:])
+		service := name
+		if pkg != "" {
+			service = pkg + "." + name
+		}
+		for _, rpc := range rpcRe.FindAllStringSubmatch(body, -1) {
+			methods = append(methods, Method{
+				Service:         service,
+				Name:            rpc[1],
+				ClientStreaming: strings.TrimSpace(rpc[2]) == "stream",
+				ServerStreaming: strings.TrimSpace(rpc[3]) == "stream",
+			})
+		}
+	}
+	return methods
+}
+
+// braceBody returns the contents of the brace block starting at text[0],
+// which must be '{'. Nested braces (rpc options) are balanced.
+func braceBody(text string) string {
+	depth := 0
+	for i, r := range text {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return text[1:i]
+			}
+		}
+	}
+	return text[1:]
+}
+
+// LoadProtoDir parses every .proto file under dir and makes the resulting
+// methods available to the probe. It replaces any previously loaded set.
+func LoadProtoDir(dir string) error {
+	var methods []Method
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".proto") {
+			return err
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		methods = append(methods, ParseProto(source)...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("grpc: loading proto files: %w", err)
+	}
+	setMethods(methods)
+	return nil
+}
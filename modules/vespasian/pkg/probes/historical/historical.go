@@ -0,0 +1,143 @@
+// Package historical mines web archives for endpoints a target used to
+// serve. Retired API routes frequently stay routable long after they drop
+// out of specs and sitemaps, which makes archive indexes a rich passive
+// source.
+package historical
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// maxResults bounds how many archived URLs are requested per source; busy
+// hosts have millions of captures.
+const maxResults = 500
+
+// Probe queries Wayback Machine and Common Crawl indexes.
+type Probe struct {
+	// WaybackURL and CommonCrawlURL override the index endpoints,
+	// mainly for tests and mirrors.
+	WaybackURL     string
+	CommonCrawlURL string
+}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "historical" }
+
+// Accepts implements probe.Probe. Archives index by hostname, so the probe
+// runs once per host — targets with explicit ports resolve to the same
+// index entries and are skipped.
+func (*Probe) Accepts(target types.Target) bool {
+	return target.Port == 0 || target.Port == 443 || target.Port == 80
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	seen := map[string]string{} // url -> origin
+	for archived, origin := range p.wayback(ctx, c, target.Host) {
+		seen[archived] = origin
+	}
+	for archived, origin := range p.commonCrawl(ctx, c, target.Host) {
+		if _, ok := seen[archived]; !ok {
+			seen[archived] = origin
+		}
+	}
+
+	urls := make([]string, 0, len(seen))
+	for archived := range seen {
+		urls = append(urls, archived)
+	}
+	sort.Strings(urls)
+
+	var endpoints []types.Endpoint
+	for _, archived := range urls {
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        archived,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "historical",
+			Confidence: types.ConfidenceLow,
+			Metadata:   map[string]string{"origin": seen[archived]},
+		})
+	}
+	return endpoints, nil
+}
+
+// wayback queries the CDX API, which returns a JSON array of rows with a
+// header row first.
+func (p *Probe) wayback(ctx context.Context, c *client.Client, host string) map[string]string {
+	base := p.WaybackURL
+	if base == "" {
+		base = "https://web.archive.org"
+	}
+	endpoint := base + "/cdx/search/cdx?output=json&fl=original&collapse=urlkey&limit=" +
+		"500&url=" + url.QueryEscape(host+"/*")
+	resp, err := c.Get(ctx, endpoint)
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	var rows [][]string
+	if err := json.Unmarshal(resp.Body, &rows); err != nil {
+		return nil
+	}
+	found := map[string]string{}
+	for i, row := range rows {
+		if i == 0 || len(row) == 0 { // header row
+			continue
+		}
+		if keep(row[0], host) {
+			found[row[0]] = "wayback"
+		}
+	}
+	return found
+}
+
+// commonCrawl queries the most recent Common Crawl index, which answers in
+// NDJSON.
+func (p *Probe) commonCrawl(ctx context.Context, c *client.Client, host string) map[string]string {
+	base := p.CommonCrawlURL
+	if base == "" {
+		base = "https://index.commoncrawl.org/CC-MAIN-latest-index"
+	}
+	endpoint := base + "?output=json&limit=500&url=" + url.QueryEscape(host+"/*")
+	resp, err := c.Get(ctx, endpoint)
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	found := map[string]string{}
+	for _, line := range strings.Split(string(resp.Body), "\n") {
+		var record struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			continue
+		}
+		if keep(record.URL, host) {
+			found[record.URL] = "commoncrawl"
+		}
+		if len(found) >= maxResults {
+			break
+		}
+	}
+	return found
+}
+
+// keep filters archive rows down to well-formed URLs on the target host.
+func keep(raw, host string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && strings.EqualFold(u.Hostname(), host)
+}
@@ -0,0 +1,127 @@
+// Package learnedpaths probes targets with paths learned from previous
+// scans. The wordlist package ranks paths by how often past engagements
+// discovered them, so targets built on familiar stacks give up their
+// endpoints without a full crawl.
+package learnedpaths
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+	"github.com/praetorian-inc/vespasian/pkg/wordlist"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// defaultMaxPaths caps how many learned paths are tried per target so an
+// old, large wordlist does not turn the probe into a bruteforce scan.
+const defaultMaxPaths = 50
+
+var (
+	mu           sync.RWMutex
+	learnedPaths []string
+	maxPaths     = defaultMaxPaths
+)
+
+// SetPaths installs the learned paths the probe tries, most promising
+// first. Without paths the probe is a no-op.
+func SetPaths(paths []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	learnedPaths = paths
+}
+
+// Probe requests learned paths and reports the ones that answer.
+type Probe struct{}
+
+// Configure implements probe.Configurable. "wordlist" loads a ranked path
+// file (same layout as the top-level wordlist_file); "max_paths" caps how
+// many paths are tried per target.
+func (*Probe) Configure(options map[string]string) error {
+	for key, value := range options {
+		switch key {
+		case "wordlist":
+			paths, err := wordlist.Load(value, 0)
+			if err != nil {
+				return fmt.Errorf("learned-paths: %w", err)
+			}
+			SetPaths(paths)
+		case "max_paths":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("learned-paths: invalid option %s=%q", key, value)
+			}
+			mu.Lock()
+			maxPaths = n
+			mu.Unlock()
+		default:
+			return fmt.Errorf("learned-paths: unknown option %q", key)
+		}
+	}
+	return nil
+}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "learned-paths" }
+
+// Priority implements probe.Prioritized: bruteforcing runs last so the
+// scheduler can skip it when an earlier stage confirmed a richer surface.
+func (*Probe) Priority() int { return 60 }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(learnedPaths) == 0 {
+		return false
+	}
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	mu.RLock()
+	paths := learnedPaths
+	limit := maxPaths
+	mu.RUnlock()
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, path := range paths {
+		resp, err := c.Get(ctx, base+path)
+		if err != nil {
+			continue
+		}
+		// 2xx/3xx means the path exists; 401/403 means it exists behind
+		// auth. Everything else is treated as absent.
+		confidence := types.ConfidenceMedium
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 400:
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			confidence = types.ConfidenceLow
+		default:
+			continue
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + path,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "learned-paths",
+			Confidence: confidence,
+			Metadata:   map[string]string{"status": strconv.Itoa(resp.StatusCode)},
+		})
+	}
+	return endpoints, nil
+}
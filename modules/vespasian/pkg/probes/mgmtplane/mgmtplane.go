@@ -0,0 +1,90 @@
+// Package mgmtplane checks for well-known management-plane endpoints
+// reachable over plain HTTP: Spring Boot actuators, Prometheus metrics,
+// Go pprof handlers, Jenkins, Elasticsearch, Kibana, Consul, and etcd.
+// Each is confirmed by a product-specific marker before it is reported,
+// and every confirmed exposure is a finding in its own right — none of
+// these belong on an untrusted network.
+package mgmtplane
+
+import (
+	"context"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// managementChecks are the locations tried against every target, with the
+// body marker (or response header) that confirms the product. Markers are
+// specific enough that an application route sharing the path does not
+// false-positive.
+var managementChecks = []struct {
+	path    string
+	marker  string // substring the body must contain; empty skips the check
+	header  string // header that must be present; empty skips the check
+	product string
+}{
+	{"/actuator", `"_links"`, "", "spring-boot-actuator"},
+	{"/actuator/env", `"propertySources"`, "", "spring-boot-actuator"},
+	{"/metrics", "# HELP", "", "prometheus-metrics"},
+	{"/debug/pprof/", "Types of profiles available", "", "go-pprof"},
+	{"/api/json", `"jobs"`, "X-Jenkins", "jenkins"},
+	{"/_cat", "=^.^=", "", "elasticsearch"},
+	{"/_cluster/health", `"cluster_name"`, "", "elasticsearch"},
+	{"/api/status", "", "kbn-name", "kibana"},
+	{"/v1/agent/self", `"Datacenter"`, "", "consul"},
+	{"/version", "etcdserver", "", "etcd"},
+}
+
+// Probe checks for exposed management-plane endpoints.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "mgmt-plane" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, check := range managementChecks {
+		resp, err := c.Get(ctx, base+check.path)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		if check.marker != "" && !strings.Contains(string(resp.Body), check.marker) {
+			continue
+		}
+		if check.header != "" && resp.Header.Get(check.header) == "" {
+			continue
+		}
+		metadata := map[string]string{
+			"risk":    "exposed-management-endpoint",
+			"product": check.product,
+		}
+		if check.header != "" {
+			// X-Jenkins carries the version, kbn-name the instance name;
+			// either way the value is worth keeping.
+			metadata[strings.ToLower(check.header)] = resp.Header.Get(check.header)
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + check.path,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "mgmt-plane",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+	}
+	return endpoints, nil
+}
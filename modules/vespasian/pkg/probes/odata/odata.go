@@ -0,0 +1,133 @@
+// Package odata discovers OData services through their service documents
+// and $metadata endpoints, enumerating exposed entity sets.
+package odata
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// roots are common OData service roots.
+var roots = []string{
+	"/odata",
+	"/odata/v4",
+	"/api/odata",
+	"/OData",
+	"/_api",
+}
+
+// Probe discovers OData services and their entity sets.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "odata" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, root := range roots {
+		sets := p.entitySets(ctx, c, base+root)
+		if sets == nil {
+			continue
+		}
+		for _, set := range sets {
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        base + root + "/" + set,
+				Method:     "GET",
+				Protocol:   types.ProtocolHTTP,
+				Source:     "odata",
+				Confidence: types.ConfidenceHigh,
+				Metadata: map[string]string{
+					"odata_root": base + root,
+					"entity_set": set,
+				},
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// entitySets returns the entity sets exposed at root, or nil when root is
+// not an OData service. The JSON service document is preferred; $metadata
+// is the fallback for services that only speak XML.
+func (p *Probe) entitySets(ctx context.Context, c *client.Client, root string) []string {
+	if resp, err := c.Get(ctx, root); err == nil && resp.StatusCode == 200 {
+		if sets := parseServiceDocument(resp.Body); sets != nil {
+			return sets
+		}
+	}
+	if resp, err := c.Get(ctx, root+"/$metadata"); err == nil && resp.StatusCode == 200 {
+		return parseMetadata(resp.Body)
+	}
+	return nil
+}
+
+// serviceDocument is an OData JSON service document.
+type serviceDocument struct {
+	Context string `json:"@odata.context"`
+	Value   []struct {
+		Name string `json:"name"`
+		Kind string `json:"kind"`
+		URL  string `json:"url"`
+	} `json:"value"`
+}
+
+func parseServiceDocument(body []byte) []string {
+	var doc serviceDocument
+	if err := json.Unmarshal(body, &doc); err != nil || doc.Context == "" {
+		return nil
+	}
+	var sets []string
+	for _, entry := range doc.Value {
+		if entry.Kind != "" && entry.Kind != "EntitySet" {
+			continue
+		}
+		if entry.Name != "" {
+			sets = append(sets, entry.Name)
+		}
+	}
+	return sets
+}
+
+// metadataDoc is the subset of an OData $metadata (EDMX) document listing
+// entity sets.
+type metadataDoc struct {
+	XMLName    xml.Name `xml:"Edmx"`
+	EntitySets []struct {
+		Name string `xml:"Name,attr"`
+	} `xml:"DataServices>Schema>EntityContainer>EntitySet"`
+}
+
+func parseMetadata(body []byte) []string {
+	if !strings.Contains(string(body), "Edmx") {
+		return nil
+	}
+	var doc metadataDoc
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+	var sets []string
+	for _, set := range doc.EntitySets {
+		if set.Name != "" {
+			sets = append(sets, set.Name)
+		}
+	}
+	return sets
+}
@@ -0,0 +1,263 @@
+// Package openapi discovers REST endpoints by fetching OpenAPI/Swagger
+// specification documents from their conventional locations and parsing
+// the path table.
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// specPaths are the locations specs are commonly served from.
+var specPaths = knowledge.SpecPaths
+
+// methodKeys are the HTTP methods an OpenAPI path item may define.
+var methodKeys = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Probe fetches and parses OpenAPI/Swagger documents.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "openapi" }
+
+// Accepts implements probe.Probe. The probe targets conventional HTTP ports.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	specURLs := make([]string, 0, len(specPaths))
+	for _, specPath := range specPaths {
+		specURLs = append(specURLs, base+specPath)
+	}
+	// Earlier stages may have spotted spec documents off the conventional
+	// paths; parse those too.
+	if discovery := probe.DiscoveryFrom(ctx); discovery != nil {
+		specURLs = append(specURLs, discovery.SpecURLs(target.Address())...)
+	}
+
+	seen := map[string]bool{}
+	var endpoints []types.Endpoint
+	for _, specURL := range specURLs {
+		if seen[specURL] {
+			continue
+		}
+		seen[specURL] = true
+		resp, err := c.Get(ctx, specURL)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		found, err := parseSpec(ctx, c, base, specURL, resp.Body)
+		if err != nil {
+			continue
+		}
+		endpoints = append(endpoints, found...)
+	}
+	return endpoints, nil
+}
+
+// spec is the subset of an OpenAPI document the probe reads.
+type spec struct {
+	Swagger string                    `json:"swagger"`
+	OpenAPI string                    `json:"openapi"`
+	Paths   map[string]map[string]any `json:"paths"`
+	Info    struct{ Title string }    `json:"info"`
+}
+
+// maxRefFetches caps external $ref documents fetched per spec.
+const maxRefFetches = 10
+
+func parseSpec(ctx context.Context, c *client.Client, base, specURL string, body []byte) ([]types.Endpoint, error) {
+	var doc spec
+	if err := json.Unmarshal(body, &doc); err != nil {
+		// Specs are often YAML served as text/plain; fall back to the
+		// minimal YAML walker.
+		yamlDoc, yerr := parseYAMLSpec(body)
+		if yerr != nil {
+			return nil, err
+		}
+		doc = *yamlDoc
+	}
+	if doc.Swagger == "" && doc.OpenAPI == "" {
+		return nil, fmt.Errorf("openapi: %s is not a spec document", specURL)
+	}
+	refBudget := maxRefFetches
+	var endpoints []types.Endpoint
+	for path, item := range doc.Paths {
+		if ref, ok := item["$ref"].(string); ok {
+			resolved := resolveRef(ctx, c, specURL, ref, &refBudget)
+			if resolved == nil {
+				continue
+			}
+			item = resolved
+		}
+		for _, method := range methodKeys {
+			op, ok := item[method]
+			if !ok {
+				continue
+			}
+			metadata := map[string]string{"spec": specURL}
+			if kind := uploadKind(op); kind != "" {
+				metadata["upload"] = kind
+			}
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        base + path,
+				Method:     strings.ToUpper(method),
+				Protocol:   types.ProtocolHTTP,
+				Source:     "openapi",
+				Confidence: types.ConfidenceHigh,
+				Metadata:   metadata,
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// resolveRef fetches the document an external path-item $ref points to,
+// relative to the spec URL, and walks the JSON-pointer fragment down to
+// the operation map. Internal refs and exhausted budgets yield nil.
+func resolveRef(ctx context.Context, c *client.Client, specURL, ref string, budget *int) map[string]any {
+	docPart, pointer, _ := strings.Cut(ref, "#")
+	if docPart == "" || *budget <= 0 {
+		return nil
+	}
+	*budget--
+	specBase, err := url.Parse(specURL)
+	if err != nil {
+		return nil
+	}
+	refURL, err := url.Parse(docPart)
+	if err != nil {
+		return nil
+	}
+	resp, err := c.Get(ctx, specBase.ResolveReference(refURL).String())
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(resp.Body, &doc); err != nil {
+		return nil
+	}
+	node := doc
+	for _, token := range strings.Split(pointer, "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = m[token]
+	}
+	item, _ := node.(map[string]any)
+	return item
+}
+
+// parseYAMLSpec recovers the version marker and path → method table from
+// a YAML spec by walking indentation — enough to enumerate endpoints
+// without a full YAML implementation.
+func parseYAMLSpec(body []byte) (*spec, error) {
+	doc := &spec{Paths: map[string]map[string]any{}}
+	inPaths := false
+	pathIndent, methodIndent := -1, -1
+	currentPath := ""
+	for _, raw := range strings.Split(string(body), "\n") {
+		trimmed := strings.TrimSpace(strings.TrimRight(raw, "\r"))
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(key, `"'`)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if indent == 0 {
+			inPaths = key == "paths"
+			switch key {
+			case "swagger":
+				doc.Swagger = value
+			case "openapi":
+				doc.OpenAPI = value
+			}
+			continue
+		}
+		if !inPaths {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(key, "/") && (pathIndent == -1 || indent == pathIndent):
+			pathIndent = indent
+			currentPath = key
+			doc.Paths[currentPath] = map[string]any{}
+		case currentPath != "" && indent > pathIndent && (methodIndent == -1 || indent == methodIndent):
+			if key == "$ref" && value != "" {
+				doc.Paths[currentPath]["$ref"] = value
+				continue
+			}
+			for _, method := range methodKeys {
+				if key == method {
+					methodIndent = indent
+					doc.Paths[currentPath][key] = map[string]any{}
+					break
+				}
+			}
+		}
+	}
+	if doc.Swagger == "" && doc.OpenAPI == "" {
+		return nil, fmt.Errorf("openapi: not a YAML spec")
+	}
+	return doc, nil
+}
+
+// uploadKind inspects an operation object for upload-capable request
+// bodies: multipart/form-data consumes (Swagger 2) or request body content
+// (OpenAPI 3), and XOP/MTOM media types. It returns "" for non-uploads.
+func uploadKind(op any) string {
+	operation, ok := op.(map[string]any)
+	if !ok {
+		return ""
+	}
+	var mediaTypes []string
+	if consumes, ok := operation["consumes"].([]any); ok {
+		for _, mt := range consumes {
+			if s, ok := mt.(string); ok {
+				mediaTypes = append(mediaTypes, s)
+			}
+		}
+	}
+	if requestBody, ok := operation["requestBody"].(map[string]any); ok {
+		if content, ok := requestBody["content"].(map[string]any); ok {
+			for mt := range content {
+				mediaTypes = append(mediaTypes, mt)
+			}
+		}
+	}
+	for _, mt := range mediaTypes {
+		mt = strings.ToLower(mt)
+		switch {
+		case strings.Contains(mt, "application/xop+xml"):
+			return "mtom"
+		case strings.HasPrefix(mt, "multipart/"):
+			return "multipart"
+		}
+	}
+	return ""
+}
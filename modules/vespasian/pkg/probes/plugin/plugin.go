@@ -0,0 +1,104 @@
+// Package plugin runs external probes over an exec protocol: the plugin
+// binary receives the target as JSON on stdin and prints discovered
+// endpoints as JSON on stdout. Teams can ship proprietary probes this way
+// without forking vespasian.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Probe executes an external plugin binary. It is registered from
+// configuration rather than an init func like the built-in probes.
+type Probe struct {
+	name    string
+	command []string
+}
+
+// New returns a plugin probe for the given name and command line. The
+// command is split on whitespace; the first word is the binary.
+func New(name, command string) (*Probe, error) {
+	argv := strings.Fields(command)
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("plugin %s: empty command", name)
+	}
+	return &Probe{name: name, command: argv}, nil
+}
+
+// pluginInput is the JSON document written to the plugin's stdin.
+type pluginInput struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"`
+}
+
+// pluginOutput is the JSON document expected on stdout. A bare endpoint
+// array is also accepted.
+type pluginOutput struct {
+	Endpoints []types.Endpoint `json:"endpoints"`
+}
+
+// Name implements probe.Probe.
+func (p *Probe) Name() string { return p.name }
+
+// Accepts implements probe.Probe. Plugins see every target and apply
+// their own filtering.
+func (p *Probe) Accepts(types.Target) bool { return true }
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, _ *client.Client) ([]types.Endpoint, error) {
+	input, err := json.Marshal(pluginInput{Host: target.Host, Port: target.Port})
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return nil, fmt.Errorf("plugin %s: %v: %s", p.name, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+
+	endpoints, err := parseOutput(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	for i := range endpoints {
+		if endpoints[i].Source == "" {
+			endpoints[i].Source = p.name
+		}
+	}
+	return endpoints, nil
+}
+
+func parseOutput(data []byte) ([]types.Endpoint, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	if trimmed[0] == '[' {
+		var endpoints []types.Endpoint
+		if err := json.Unmarshal(trimmed, &endpoints); err != nil {
+			return nil, err
+		}
+		return endpoints, nil
+	}
+	var out pluginOutput
+	if err := json.Unmarshal(trimmed, &out); err != nil {
+		return nil, err
+	}
+	return out.Endpoints, nil
+}
@@ -0,0 +1,30 @@
+// Package probes registers every built-in probe. Importing it for side
+// effects makes the full probe set available through the probe registry.
+package probes
+
+import (
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/broker"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/containerapi"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/crawl"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/errleak"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/errverbosity"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/fingerprint"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/graphql"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/grpc"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/historical"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/learnedpaths"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/mgmtplane"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/odata"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/openapi"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/runtimeconfig"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/serverstatus"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/soap"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/specui"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/ssdp"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/sso"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/upload"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/versionheaders"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/wasm"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/websocket"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes/wsinspection"
+)
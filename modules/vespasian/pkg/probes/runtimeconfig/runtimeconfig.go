@@ -0,0 +1,132 @@
+// Package runtimeconfig discovers the runtime configuration endpoints
+// SPAs and containerized apps serve next to their bundles (config.json,
+// env.js). These files routinely disclose API base URLs, feature flags,
+// and keys, and the URLs they reference widen the scan surface.
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// configPaths are the runtime configuration locations tried per target.
+var configPaths = []string{
+	"/config.json",
+	"/config.js",
+	"/env.js",
+	"/env.json",
+	"/settings.js",
+	"/settings.json",
+	"/__ENV.js",
+	"/assets/config.json",
+	"/assets/env.js",
+}
+
+// urlRe matches absolute http(s) URLs inside config values.
+var urlRe = regexp.MustCompile(`https?://[^\s"'` + "`" + `\\]+`)
+
+// Probe discovers runtime configuration files and the API URLs they name.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "runtime-config" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, path := range configPaths {
+		resp, err := c.Get(ctx, base+path)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		// SPA servers answer unknown paths with index.html; an HTML body
+		// means the config file does not actually exist.
+		body := string(resp.Body)
+		if strings.Contains(strings.ToLower(body[:min(len(body), 256)]), "<html") {
+			continue
+		}
+
+		metadata := map[string]string{}
+		if flags := featureFlags(resp.Body); flags != "" {
+			metadata["feature_flags"] = flags
+		}
+		if keys := keyNames(body); keys != "" {
+			metadata["key_names"] = keys
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + path,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "runtime-config",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+
+		// Every URL the config references is a scan seed: the API the SPA
+		// talks to usually lives on another host or path entirely.
+		for _, u := range urlRe.FindAllString(body, -1) {
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        strings.TrimRight(u, "/,;"),
+				Method:     "GET",
+				Protocol:   types.ProtocolHTTP,
+				Source:     "runtime-config",
+				Confidence: types.ConfidenceMedium,
+				Metadata:   map[string]string{"origin": base + path},
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// featureFlags extracts boolean-valued keys from a JSON config body.
+func featureFlags(body []byte) string {
+	var doc map[string]any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return ""
+	}
+	var flags []string
+	for key, value := range doc {
+		if _, ok := value.(bool); ok {
+			flags = append(flags, key)
+		}
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return strings.Join(flags, ", ")
+}
+
+// keyRe matches config keys that look like they hold credentials.
+var keyRe = regexp.MustCompile(`(?i)"?([A-Za-z0-9_]*(?:api[_-]?key|secret|token|client[_-]?id)[A-Za-z0-9_]*)"?\s*[:=]`)
+
+// keyNames lists the credential-looking key names in a config body. Only
+// the names are reported, never the values.
+func keyNames(body string) string {
+	seen := map[string]bool{}
+	var names []string
+	for _, match := range keyRe.FindAllStringSubmatch(body, -1) {
+		name := match[1]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
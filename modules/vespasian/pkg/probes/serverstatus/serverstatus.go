@@ -0,0 +1,140 @@
+// Package serverstatus discovers live endpoints from exposed web server
+// status pages. Apache mod_status and friends list the request URIs the
+// workers are currently serving — internal endpoints no crawl would find —
+// and the pages themselves are findings: they should not face the
+// internet. An `OPTIONS *` request is sent as well to read the
+// server-wide method list.
+package serverstatus
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// statusPages are well-known status and info page locations, with the
+// body marker that confirms each one is the real thing rather than an
+// application route that happens to share the path.
+var statusPages = []struct {
+	path   string
+	marker string
+	kind   string
+}{
+	{"/server-status", "Apache Server Status", "apache mod_status"},
+	{"/server-info", "Apache Server Information", "apache mod_info"},
+	{"/nginx_status", "Active connections:", "nginx stub_status"},
+	{"/status", "Active connections:", "nginx stub_status"},
+	{"/status", "Scoreboard", "php-fpm status"},
+}
+
+// workerRequestRe matches the request column of an Apache scoreboard
+// table, e.g. "GET /internal/admin HTTP/1.1".
+var workerRequestRe = regexp.MustCompile(`(?:GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS) (/[^ <"]*) HTTP/`)
+
+// Probe queries server status pages and OPTIONS *.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "server-status" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+
+	if allow := optionsStar(ctx, c, base); allow != "" {
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + "/",
+			Method:     "OPTIONS",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "server-status",
+			Confidence: types.ConfidenceMedium,
+			Metadata:   map[string]string{"allowed_methods": allow, "origin": "OPTIONS *"},
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, page := range statusPages {
+		if seen[page.path] {
+			continue
+		}
+		resp, err := c.Get(ctx, base+page.path)
+		if err != nil || resp.StatusCode != 200 {
+			seen[page.path] = true
+			continue
+		}
+		body := string(resp.Body)
+		if !strings.Contains(body, page.marker) {
+			continue
+		}
+		seen[page.path] = true
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + page.path,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "server-status",
+			Confidence: types.ConfidenceHigh,
+			Metadata: map[string]string{
+				"exposed_status_page": "true",
+				"page":                page.kind,
+			},
+		})
+		for _, uri := range workerRequests(body, base) {
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        uri,
+				Method:     "GET",
+				Protocol:   types.ProtocolHTTP,
+				Source:     "server-status",
+				Confidence: types.ConfidenceMedium,
+				Metadata:   map[string]string{"origin": base + page.path},
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// optionsStar sends `OPTIONS *` — the server-wide form, not a path — and
+// returns the Allow header if the server answered with one.
+func optionsStar(ctx context.Context, c *client.Client, base string) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, base, nil)
+	if err != nil {
+		return ""
+	}
+	req.URL.Opaque = "*"
+	resp, err := c.Do(req)
+	if err != nil || resp.StatusCode >= 400 {
+		return ""
+	}
+	return resp.Header.Get("Allow")
+}
+
+// workerRequests extracts the in-flight request URIs from a scoreboard
+// table, skipping the status page's own URI.
+func workerRequests(body, base string) []string {
+	seen := map[string]bool{}
+	var uris []string
+	for _, match := range workerRequestRe.FindAllStringSubmatch(body, -1) {
+		path := match[1]
+		if path == "" || strings.HasPrefix(path, "/server-status") || seen[path] {
+			continue
+		}
+		seen[path] = true
+		uris = append(uris, base+path)
+	}
+	return uris
+}
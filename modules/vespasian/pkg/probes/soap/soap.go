@@ -0,0 +1,145 @@
+// Package soap discovers SOAP services by fetching WSDL documents from
+// their conventional locations and enumerating the operations they bind.
+package soap
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// wsdlPaths are locations WSDL documents are commonly served from.
+var wsdlPaths = knowledge.WSDLPaths
+
+var (
+	invokeMu      sync.RWMutex
+	invokeEnabled bool
+)
+
+// SetInvoke enables the liveness check: each bound address is sent one
+// deliberately empty SOAP envelope so the fault response confirms a live
+// SOAP stack behind the WSDL.
+func SetInvoke(enabled bool) {
+	invokeMu.Lock()
+	invokeEnabled = enabled
+	invokeMu.Unlock()
+}
+
+func invokeOn() bool {
+	invokeMu.RLock()
+	defer invokeMu.RUnlock()
+	return invokeEnabled
+}
+
+// Probe fetches and parses WSDL documents.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "soap" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, path := range wsdlPaths {
+		resp, err := c.Get(ctx, base+path)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		if !strings.Contains(string(resp.Body), "definitions") {
+			continue
+		}
+		operations, err := ParseWSDL(resp.Body)
+		if err != nil {
+			continue
+		}
+		liveness := map[string]map[string]string{}
+		for _, op := range operations {
+			address := op.Address
+			if address == "" {
+				address = base + strings.SplitN(path, "?", 2)[0]
+			}
+			metadata := map[string]string{
+				"wsdl":        base + path,
+				"service":     op.Service,
+				"operation":   op.Name,
+				"soap_action": op.SOAPAction,
+			}
+			if op.Namespace != "" {
+				metadata["namespace"] = op.Namespace
+			}
+			if op.InputMessage != "" {
+				metadata["input_message"] = op.InputMessage
+			}
+			if op.Parts != "" {
+				metadata["input_parts"] = op.Parts
+			}
+			if invokeOn() {
+				extra, ok := liveness[address]
+				if !ok {
+					extra = checkLiveness(ctx, c, address)
+					liveness[address] = extra
+				}
+				for key, value := range extra {
+					metadata[key] = value
+				}
+			}
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        address,
+				Method:     "POST",
+				Protocol:   types.ProtocolSOAP,
+				Source:     "soap",
+				Confidence: types.ConfidenceHigh,
+				Metadata:   metadata,
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// emptyEnvelope carries no body elements; a live SOAP stack answers it
+// with a fault rather than acting on it.
+const emptyEnvelope = `<?xml version="1.0" encoding="utf-8"?>` +
+	`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+	`<soap:Body/></soap:Envelope>`
+
+var (
+	faultCodeRe   = regexp.MustCompile(`<(?:\w+:)?faultcode[^>]*>([^<]{1,200})`)
+	faultStringRe = regexp.MustCompile(`<(?:\w+:)?faultstring[^>]*>([^<]{1,200})`)
+)
+
+// checkLiveness posts the empty envelope to a bound address and records
+// whether a SOAP stack answered, plus any fault details it volunteered.
+func checkLiveness(ctx context.Context, c *client.Client, address string) map[string]string {
+	resp, err := c.Post(ctx, address, "text/xml; charset=utf-8", strings.NewReader(emptyEnvelope))
+	if err != nil {
+		return map[string]string{"live": "false"}
+	}
+	body := string(resp.Body)
+	extra := map[string]string{"live": "false"}
+	if strings.Contains(body, "Envelope") || strings.Contains(body, "Fault") {
+		extra["live"] = "true"
+	}
+	if m := faultCodeRe.FindStringSubmatch(body); m != nil {
+		extra["fault_code"] = strings.TrimSpace(m[1])
+	}
+	if m := faultStringRe.FindStringSubmatch(body); m != nil {
+		extra["fault_string"] = strings.TrimSpace(m[1])
+	}
+	return extra
+}
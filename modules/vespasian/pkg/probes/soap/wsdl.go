@@ -0,0 +1,165 @@
+package soap
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// wsdlDefinitions is the subset of a WSDL document needed to enumerate
+// operations: bindings carry SOAP actions, service ports carry the
+// addresses the bindings are reachable at. Field names are unqualified so
+// both wsdl/soap 1.1 and 1.2 namespaces match.
+type wsdlDefinitions struct {
+	XMLName         xml.Name       `xml:"definitions"`
+	TargetNamespace string         `xml:"targetNamespace,attr"`
+	Messages        []wsdlMessage  `xml:"message"`
+	PortTypes       []wsdlPortType `xml:"portType"`
+	Bindings        []wsdlBinding  `xml:"binding"`
+	Services        []wsdlService  `xml:"service"`
+}
+
+type wsdlMessage struct {
+	Name  string `xml:"name,attr"`
+	Parts []struct {
+		Name    string `xml:"name,attr"`
+		Element string `xml:"element,attr"`
+		Type    string `xml:"type,attr"`
+	} `xml:"part"`
+}
+
+type wsdlPortType struct {
+	Name       string `xml:"name,attr"`
+	Operations []struct {
+		Name  string `xml:"name,attr"`
+		Input struct {
+			Message string `xml:"message,attr"`
+		} `xml:"input"`
+	} `xml:"operation"`
+}
+
+type wsdlBinding struct {
+	Name       string          `xml:"name,attr"`
+	Type       string          `xml:"type,attr"`
+	Operations []wsdlOperation `xml:"operation"`
+}
+
+type wsdlOperation struct {
+	Name    string `xml:"name,attr"`
+	SOAPOps []struct {
+		SOAPAction string `xml:"soapAction,attr"`
+	} `xml:"operation"`
+}
+
+type wsdlService struct {
+	Name  string     `xml:"name,attr"`
+	Ports []wsdlPort `xml:"port"`
+}
+
+type wsdlPort struct {
+	Name      string `xml:"name,attr"`
+	Binding   string `xml:"binding,attr"`
+	Addresses []struct {
+		Location string `xml:"location,attr"`
+	} `xml:"address"`
+}
+
+// Operation is one SOAP operation extracted from a WSDL document, bound to
+// the address it is invocable at. Namespace, InputMessage, and Parts carry
+// what a downstream tester needs to build a valid request envelope.
+type Operation struct {
+	Service      string
+	Binding      string
+	Name         string
+	SOAPAction   string
+	Address      string
+	Namespace    string
+	InputMessage string
+	// Parts lists the input message parts as "name=element" (or
+	// "name=type"), comma-joined.
+	Parts string
+}
+
+// ParseWSDL extracts operations with their SOAP action URIs and binding
+// addresses from a WSDL document.
+func ParseWSDL(body []byte) ([]Operation, error) {
+	var doc wsdlDefinitions
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+
+	bindings := map[string]wsdlBinding{}
+	for _, binding := range doc.Bindings {
+		bindings[binding.Name] = binding
+	}
+	portTypes := map[string]wsdlPortType{}
+	for _, portType := range doc.PortTypes {
+		portTypes[portType.Name] = portType
+	}
+	messages := map[string]wsdlMessage{}
+	for _, message := range doc.Messages {
+		messages[message.Name] = message
+	}
+
+	var operations []Operation
+	for _, service := range doc.Services {
+		for _, port := range service.Ports {
+			address := ""
+			if len(port.Addresses) > 0 {
+				address = port.Addresses[0].Location
+			}
+			// Binding references are QNames; the local part keys the map.
+			binding, ok := bindings[localPart(port.Binding)]
+			if !ok {
+				continue
+			}
+			portType := portTypes[localPart(binding.Type)]
+			for _, op := range binding.Operations {
+				operation := Operation{
+					Service:   service.Name,
+					Binding:   binding.Name,
+					Name:      op.Name,
+					Address:   address,
+					Namespace: doc.TargetNamespace,
+				}
+				if len(op.SOAPOps) > 0 {
+					operation.SOAPAction = op.SOAPOps[0].SOAPAction
+				}
+				for _, ptOp := range portType.Operations {
+					if ptOp.Name != op.Name {
+						continue
+					}
+					operation.InputMessage = localPart(ptOp.Input.Message)
+					operation.Parts = messageParts(messages[operation.InputMessage])
+					break
+				}
+				operations = append(operations, operation)
+			}
+		}
+	}
+	return operations, nil
+}
+
+// localPart strips the namespace prefix from a QName reference.
+func localPart(qname string) string {
+	if i := strings.LastIndex(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}
+
+// messageParts renders a message's parts as "name=element" pairs.
+func messageParts(message wsdlMessage) string {
+	var parts []string
+	for _, part := range message.Parts {
+		ref := part.Element
+		if ref == "" {
+			ref = part.Type
+		}
+		if ref == "" {
+			parts = append(parts, part.Name)
+			continue
+		}
+		parts = append(parts, part.Name+"="+localPart(ref))
+	}
+	return strings.Join(parts, ", ")
+}
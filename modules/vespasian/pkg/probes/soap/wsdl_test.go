@@ -0,0 +1,70 @@
+package soap_test
+
+import (
+	"testing"
+
+	"github.com/praetorian-inc/vespasian/pkg/probes/soap"
+)
+
+const orderWSDL = `<?xml version="1.0"?>
+<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+             xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+             xmlns:tns="urn:orders"
+             targetNamespace="urn:orders">
+  <message name="GetOrderInput">
+    <part name="orderId" element="tns:OrderID"/>
+  </message>
+  <portType name="OrderPortType">
+    <operation name="GetOrder">
+      <input message="tns:GetOrderInput"/>
+    </operation>
+  </portType>
+  <binding name="OrderBinding" type="tns:OrderPortType">
+    <soap:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+    <operation name="GetOrder">
+      <soap:operation soapAction="urn:GetOrder"/>
+    </operation>
+  </binding>
+  <service name="OrderService">
+    <port name="OrderPort" binding="tns:OrderBinding">
+      <soap:address location="http://target.invalid/services/orders"/>
+    </port>
+  </service>
+</definitions>`
+
+// TestParseWSDL asserts operations come out bound to their SOAP action
+// and service address, with the input message resolved through the port
+// type so a tester can build a valid envelope.
+func TestParseWSDL(t *testing.T) {
+	operations, err := soap.ParseWSDL([]byte(orderWSDL))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(operations) != 1 {
+		t.Fatalf("got %d operations, want 1", len(operations))
+	}
+	op := operations[0]
+	if op.Name != "GetOrder" || op.Service != "OrderService" || op.Binding != "OrderBinding" {
+		t.Errorf("operation = %+v, want GetOrder on OrderService/OrderBinding", op)
+	}
+	if op.SOAPAction != "urn:GetOrder" {
+		t.Errorf("SOAPAction = %q, want urn:GetOrder", op.SOAPAction)
+	}
+	if op.Address != "http://target.invalid/services/orders" {
+		t.Errorf("address = %q, want the service port location", op.Address)
+	}
+	if op.Namespace != "urn:orders" {
+		t.Errorf("namespace = %q, want the target namespace", op.Namespace)
+	}
+	if op.InputMessage != "GetOrderInput" || op.Parts != "orderId=OrderID" {
+		t.Errorf("input = %q parts %q, want the resolved message and parts", op.InputMessage, op.Parts)
+	}
+}
+
+// TestParseWSDLRejectsNonXML asserts HTML error pages served where a
+// WSDL was expected surface as errors, not empty operation lists.
+func TestParseWSDLRejectsNonXML(t *testing.T) {
+	if _, err := soap.ParseWSDL([]byte("<html><body>404</body></html>")); err == nil {
+		t.Error("HTML page parsed as WSDL without error")
+	}
+}
@@ -0,0 +1,143 @@
+// Package specui detects API documentation UIs — Swagger UI, Redoc,
+// RapiDoc, Stoplight Elements — and extracts the spec URL they are
+// configured with. Many applications host the UI at an obscure path while
+// the raw spec lives somewhere non-standard; the UI's own configuration
+// is the most reliable pointer to it.
+package specui
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// uiPaths are locations documentation UIs are commonly served from.
+var uiPaths = []string{
+	"/swagger-ui.html",
+	"/swagger-ui/",
+	"/swagger/index.html",
+	"/docs",
+	"/docs/",
+	"/api/docs",
+	"/apidocs",
+	"/redoc",
+	"/rapidoc",
+	"/reference",
+}
+
+// uiMarkers identify the documentation UI from page markup.
+var uiMarkers = []struct {
+	marker string
+	ui     string
+}{
+	{"SwaggerUIBundle", "swagger-ui"},
+	{"swagger-ui.css", "swagger-ui"},
+	{"id=\"swagger-ui\"", "swagger-ui"},
+	{"redoc.standalone", "redoc"},
+	{"<redoc", "redoc"},
+	{"<rapi-doc", "rapidoc"},
+	{"<elements-api", "stoplight-elements"},
+	{"@stoplight/elements", "stoplight-elements"},
+}
+
+// specURLPatterns pull the configured spec URL out of the HTML/JS:
+// SwaggerUIBundle({url: "..."}) and its configUrl variant, Redoc and
+// RapiDoc spec-url attributes, and Stoplight's apiDescriptionUrl.
+var specURLPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`url\s*[:=]\s*["']([^"']+\.(?:json|yaml|yml)[^"']*)["']`),
+	regexp.MustCompile(`url\s*:\s*["']([^"']*api-docs[^"']*)["']`),
+	regexp.MustCompile(`configUrl\s*:\s*["']([^"']+)["']`),
+	regexp.MustCompile(`spec-url\s*=\s*["']([^"']+)["']`),
+	regexp.MustCompile(`apiDescriptionUrl\s*=?\s*["']([^"']+)["']`),
+}
+
+// Probe detects documentation UIs and their spec URLs.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "spec-ui" }
+
+// Priority implements probe.Prioritized: spec URLs found here feed the
+// OpenAPI parser in a later band.
+func (*Probe) Priority() int { return 20 }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	discovery := probe.DiscoveryFrom(ctx)
+	var endpoints []types.Endpoint
+	for _, uiPath := range uiPaths {
+		pageURL := base + uiPath
+		resp, err := c.Get(ctx, pageURL)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		body := string(resp.Body)
+		ui := detectUI(body)
+		if ui == "" {
+			continue
+		}
+		metadata := map[string]string{"ui": ui}
+		if specURL := extractSpecURL(pageURL, body); specURL != "" {
+			metadata["spec_url"] = specURL
+			if discovery != nil {
+				discovery.AddSpecURL(target.Address(), specURL)
+			}
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        pageURL,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "spec-ui",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+	}
+	return endpoints, nil
+}
+
+// detectUI names the documentation UI a page belongs to, or "".
+func detectUI(body string) string {
+	for _, rule := range uiMarkers {
+		if strings.Contains(body, rule.marker) {
+			return rule.ui
+		}
+	}
+	return ""
+}
+
+// extractSpecURL pulls the first configured spec URL from the page and
+// resolves it against the page URL.
+func extractSpecURL(pageURL, body string) string {
+	for _, pattern := range specURLPatterns {
+		match := pattern.FindStringSubmatch(body)
+		if match == nil {
+			continue
+		}
+		page, err := url.Parse(pageURL)
+		if err != nil {
+			return match[1]
+		}
+		ref, err := url.Parse(match[1])
+		if err != nil {
+			continue
+		}
+		return page.ResolveReference(ref).String()
+	}
+	return ""
+}
@@ -0,0 +1,226 @@
+// Package ssdp discovers UPnP device APIs. A unicast M-SEARCH to the
+// target's SSDP port yields device description locations; the descriptions
+// enumerate SOAP control URLs and their actions — the API surface of IoT
+// and embedded devices in internal assessments.
+package ssdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// ssdpPort is the standard SSDP UDP port.
+const ssdpPort = "1900"
+
+// responseWindow is how long the probe collects M-SEARCH responses.
+const responseWindow = 3 * time.Second
+
+// Probe harvests UPnP device description APIs over SSDP.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "ssdp" }
+
+// Category implements probe.Categorized: discovery runs over UDP, so it
+// must not be gated on HTTP liveness.
+func (*Probe) Category() string { return probe.CategoryRaw }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return target.Port == 0 || target.Port == 1900
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	locations, err := search(ctx, target.Host)
+	if err != nil || len(locations) == 0 {
+		// No SSDP responder is the common case; not an error worth a log.
+		return nil, nil
+	}
+
+	var endpoints []types.Endpoint
+	for _, location := range locations {
+		endpoints = append(endpoints, describe(ctx, c, location)...)
+	}
+	return endpoints, nil
+}
+
+// search sends a unicast M-SEARCH to host:1900 and returns the LOCATION
+// headers of every response received within the window.
+func search(ctx context.Context, host string) ([]string, error) {
+	conn, err := net.Dial("udp", net.JoinHostPort(host, ssdpPort))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	request := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + net.JoinHostPort(host, ssdpPort) + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n" +
+		"ST: upnp:rootdevice\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(responseWindow)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetReadDeadline(deadline)
+
+	seen := map[string]bool{}
+	var locations []string
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		if location := parseLocation(buf[:n]); location != "" && !seen[location] {
+			seen[location] = true
+			locations = append(locations, location)
+		}
+	}
+	return locations, nil
+}
+
+// parseLocation extracts the LOCATION header from an SSDP response.
+func parseLocation(response []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(response))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "location") {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// deviceDescription is the subset of a UPnP device description naming
+// services.
+type deviceDescription struct {
+	Device upnpDevice `xml:"device"`
+}
+
+type upnpDevice struct {
+	FriendlyName string        `xml:"friendlyName"`
+	DeviceType   string        `xml:"deviceType"`
+	Services     []upnpService `xml:"serviceList>service"`
+	Devices      []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+	SCPDURL     string `xml:"SCPDURL"`
+}
+
+// scpd is the subset of a service control protocol description naming
+// actions.
+type scpd struct {
+	Actions []struct {
+		Name string `xml:"name"`
+	} `xml:"actionList>action"`
+}
+
+// describe fetches a device description and emits one SOAP endpoint per
+// control URL, annotated with the actions its SCPD declares.
+func describe(ctx context.Context, c *client.Client, location string) []types.Endpoint {
+	resp, err := c.Get(ctx, location)
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	var description deviceDescription
+	if err := xml.Unmarshal(resp.Body, &description); err != nil {
+		return nil
+	}
+
+	var endpoints []types.Endpoint
+	var walk func(device upnpDevice)
+	walk = func(device upnpDevice) {
+		for _, service := range device.Services {
+			controlURL := resolve(location, service.ControlURL)
+			if controlURL == "" {
+				continue
+			}
+			metadata := map[string]string{
+				"service_type": service.ServiceType,
+				"device":       device.FriendlyName,
+			}
+			if actions := serviceActions(ctx, c, resolve(location, service.SCPDURL)); actions != "" {
+				metadata["actions"] = actions
+			}
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        controlURL,
+				Method:     http.MethodPost,
+				Protocol:   types.ProtocolSOAP,
+				Source:     "ssdp",
+				Confidence: types.ConfidenceHigh,
+				Metadata:   metadata,
+			})
+		}
+		for _, child := range device.Devices {
+			walk(child)
+		}
+	}
+	walk(description.Device)
+	return endpoints
+}
+
+// serviceActions fetches a service's SCPD and lists its action names.
+func serviceActions(ctx context.Context, c *client.Client, scpdURL string) string {
+	if scpdURL == "" {
+		return ""
+	}
+	resp, err := c.Get(ctx, scpdURL)
+	if err != nil || resp.StatusCode != 200 {
+		return ""
+	}
+	var doc scpd
+	if err := xml.Unmarshal(resp.Body, &doc); err != nil {
+		return ""
+	}
+	names := make([]string, 0, len(doc.Actions))
+	for _, action := range doc.Actions {
+		if action.Name != "" {
+			names = append(names, action.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// resolve turns a control or SCPD URL, often relative, into an absolute
+// URL against the description location.
+func resolve(location, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return ref
+	}
+	base := location
+	if i := strings.Index(base, "://"); i >= 0 {
+		if j := strings.Index(base[i+3:], "/"); j >= 0 {
+			base = base[:i+3+j]
+		}
+	}
+	if !strings.HasPrefix(ref, "/") {
+		ref = "/" + ref
+	}
+	return base + ref
+}
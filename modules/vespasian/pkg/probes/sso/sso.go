@@ -0,0 +1,161 @@
+// Package sso maps single-sign-on redirect chains without credentials.
+// Walking a login path hop by hop (application → OAuth authorize → IdP)
+// exposes the identity endpoints, client_ids, redirect_uris, and scopes an
+// application depends on, and flags overly broad redirect_uri values that
+// invite token theft.
+package sso
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// loginPaths are the entry points whose redirect chains are walked.
+var loginPaths = []string{
+	"/login",
+	"/signin",
+	"/auth/login",
+	"/account/login",
+	"/sso/login",
+	"/oauth/authorize",
+	"/oauth2/authorize",
+}
+
+// maxHops bounds a redirect chain walk.
+const maxHops = 10
+
+// Probe maps SSO redirect chains.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "sso" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	seen := map[string]bool{}
+	var endpoints []types.Endpoint
+	for _, path := range loginPaths {
+		for _, endpoint := range walk(ctx, c, base+path) {
+			key := endpoint.URL + " " + endpoint.Metadata["client_id"]
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+// walk follows the redirect chain from start, emitting one endpoint per
+// authorize URL encountered along the way.
+func walk(ctx context.Context, c *client.Client, start string) []types.Endpoint {
+	var endpoints []types.Endpoint
+	current := start
+	previous := ""
+	for hop := 0; hop < maxHops; hop++ {
+		resp, err := c.GetNoRedirect(ctx, current)
+		if err != nil {
+			return endpoints
+		}
+		if endpoint, ok := authorizeEndpoint(current, previous); ok {
+			endpoints = append(endpoints, endpoint)
+		}
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+			return endpoints
+		}
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return endpoints
+		}
+		next, err := url.Parse(current)
+		if err != nil {
+			return endpoints
+		}
+		ref, err := url.Parse(location)
+		if err != nil {
+			return endpoints
+		}
+		previous = current
+		current = next.ResolveReference(ref).String()
+	}
+	return endpoints
+}
+
+// authorizeEndpoint recognizes an OAuth/OIDC authorize URL and distills
+// its parameters into an endpoint finding. referrer records which hop sent
+// the client here.
+func authorizeEndpoint(rawURL, referrer string) (types.Endpoint, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return types.Endpoint{}, false
+	}
+	query := u.Query()
+	clientID := query.Get("client_id")
+	if clientID == "" || (query.Get("response_type") == "" && query.Get("scope") == "") {
+		return types.Endpoint{}, false
+	}
+
+	metadata := map[string]string{"client_id": clientID}
+	for _, param := range []string{"response_type", "scope", "redirect_uri", "audience"} {
+		if value := query.Get(param); value != "" {
+			metadata[param] = value
+		}
+	}
+	if referrer != "" {
+		metadata["referrer"] = referrer
+	}
+	if risk := redirectURIRisk(query.Get("redirect_uri")); risk != "" {
+		metadata["redirect_uri_risk"] = risk
+	}
+
+	stripped := *u
+	stripped.RawQuery = ""
+	stripped.Fragment = ""
+	return types.Endpoint{
+		URL:        stripped.String(),
+		Method:     "GET",
+		Protocol:   types.ProtocolHTTP,
+		Source:     "sso",
+		Confidence: types.ConfidenceHigh,
+		Metadata:   metadata,
+	}, true
+}
+
+// redirectURIRisk flags redirect_uri values broad enough to aid token
+// theft: wildcards, bare origins, and plain HTTP callbacks.
+func redirectURIRisk(redirectURI string) string {
+	if redirectURI == "" {
+		return ""
+	}
+	if strings.Contains(redirectURI, "*") {
+		return "wildcard"
+	}
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return ""
+	}
+	if u.Path == "" || u.Path == "/" {
+		return "bare origin"
+	}
+	if u.Scheme == "http" && u.Hostname() != "localhost" && u.Hostname() != "127.0.0.1" {
+		return "plaintext callback"
+	}
+	return ""
+}
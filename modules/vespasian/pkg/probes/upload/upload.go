@@ -0,0 +1,125 @@
+// Package upload identifies endpoints that accept file uploads —
+// multipart/form-data HTML forms, conventional upload paths, and SOAP
+// MTOM/XOP services. Upload-capable endpoints are high-value targets for
+// follow-on testing, so they are tagged distinctly in the inventory.
+package upload
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// uploadPaths are conventional upload endpoint locations checked with
+// OPTIONS requests.
+var uploadPaths = []string{
+	"/upload",
+	"/uploads",
+	"/api/upload",
+	"/api/v1/upload",
+	"/files",
+	"/api/files",
+	"/attachments",
+}
+
+// formRe matches an HTML form tag; individual attributes are extracted
+// separately because attribute order is not fixed.
+var (
+	formRe    = regexp.MustCompile(`(?is)<form\b[^>]*>`)
+	actionRe  = regexp.MustCompile(`(?i)action\s*=\s*["']([^"']+)["']`)
+	enctypeRe = regexp.MustCompile(`(?i)enctype\s*=\s*["']([^"']+)["']`)
+)
+
+// Probe identifies upload-capable endpoints.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "upload" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+
+	// Multipart forms on the landing page.
+	if resp, err := c.Get(ctx, base+"/"); err == nil && resp.StatusCode == 200 {
+		endpoints = append(endpoints, formsFromHTML(base, resp.Body)...)
+	}
+
+	// Conventional upload paths answered with OPTIONS.
+	for _, path := range uploadPaths {
+		req, err := http.NewRequestWithContext(ctx, http.MethodOptions, base+path, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := c.Do(req)
+		if err != nil || resp.StatusCode >= 400 {
+			continue
+		}
+		allow := resp.Header.Get("Allow")
+		if allow == "" || !strings.Contains(strings.ToUpper(allow), "POST") {
+			continue
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + path,
+			Method:     http.MethodPost,
+			Protocol:   types.ProtocolHTTP,
+			Source:     "upload",
+			Confidence: types.ConfidenceLow,
+			Metadata: map[string]string{
+				"upload": "multipart",
+				"allow":  allow,
+			},
+		})
+	}
+	return endpoints, nil
+}
+
+// formsFromHTML extracts upload-capable form actions from an HTML page.
+func formsFromHTML(base string, body []byte) []types.Endpoint {
+	var endpoints []types.Endpoint
+	for _, form := range formRe.FindAllString(string(body), -1) {
+		enctype := enctypeRe.FindStringSubmatch(form)
+		if enctype == nil || !strings.EqualFold(enctype[1], "multipart/form-data") {
+			continue
+		}
+		action := "/"
+		if m := actionRe.FindStringSubmatch(form); m != nil {
+			action = m[1]
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        resolve(base, action),
+			Method:     http.MethodPost,
+			Protocol:   types.ProtocolHTTP,
+			Source:     "upload",
+			Confidence: types.ConfidenceMedium,
+			Metadata:   map[string]string{"upload": "multipart", "origin": "html-form"},
+		})
+	}
+	return endpoints
+}
+
+func resolve(base, action string) string {
+	if strings.HasPrefix(action, "http://") || strings.HasPrefix(action, "https://") {
+		return action
+	}
+	if !strings.HasPrefix(action, "/") {
+		action = "/" + action
+	}
+	return base + action
+}
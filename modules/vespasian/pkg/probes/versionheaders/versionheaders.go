@@ -0,0 +1,73 @@
+// Package versionheaders discovers API version negotiation surfaces by
+// inspecting response headers. Services that advertise their version in a
+// header usually accept other versions through the matching request
+// header, which widens the test surface beyond the URL paths in use.
+package versionheaders
+
+import (
+	"context"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// versionHeaders are response headers that disclose API versioning.
+var versionHeaders = []string{
+	"X-Api-Version",
+	"Api-Version",
+	"X-Version",
+	"X-Api-Revision",
+	"Api-Supported-Versions",
+	"Api-Deprecated-Versions",
+}
+
+// checkPaths are the locations probed for version headers.
+var checkPaths = []string{"/", "/api", "/api/v1", "/api/v2"}
+
+// Probe discovers version negotiation headers.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "version-headers" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, path := range checkPaths {
+		resp, err := c.Get(ctx, base+path)
+		if err != nil || resp.StatusCode >= 500 {
+			continue
+		}
+		metadata := map[string]string{}
+		for _, header := range versionHeaders {
+			if value := resp.Header.Get(header); value != "" {
+				metadata[strings.ToLower(header)] = value
+			}
+		}
+		if len(metadata) == 0 {
+			continue
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + path,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "version-headers",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,158 @@
+// Package wasm discovers API endpoints embedded in WebAssembly bundles.
+// Blazor and Rust-WASM frontends compile their API clients into .wasm
+// assets whose string data names the endpoints they call — surface the JS
+// parser never sees.
+package wasm
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// wasmRefRe finds .wasm asset references in page and script bodies.
+var wasmRefRe = regexp.MustCompile(`["'(]([^"'()\s]+\.wasm)["')]`)
+
+// pagePaths are the pages inspected for .wasm references. Blazor keeps
+// its bundle manifest under _framework/.
+var pagePaths = []string{"/", "/index.html", "/_framework/blazor.boot.json"}
+
+// urlRe matches absolute URLs inside extracted string data.
+var urlRe = regexp.MustCompile(`https?://[\x21-\x7e]+`)
+
+// apiPathRe matches relative API-looking paths in string data: rooted,
+// multi-segment, starting with a conventional API prefix.
+var apiPathRe = regexp.MustCompile(`/(?:api|graphql|rest|v[0-9]+)(?:/[A-Za-z0-9_.{}-]+)*`)
+
+// maxBundles caps how many .wasm assets are downloaded per target.
+const maxBundles = 5
+
+// Probe extracts embedded API endpoints from WebAssembly bundles.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "wasm" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+
+	seen := map[string]bool{}
+	var bundles []string
+	for _, path := range pagePaths {
+		resp, err := c.Get(ctx, base+path)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		for _, match := range wasmRefRe.FindAllStringSubmatch(string(resp.Body), -1) {
+			ref := resolveRef(base, match[1])
+			if ref != "" && !seen[ref] {
+				seen[ref] = true
+				bundles = append(bundles, ref)
+			}
+		}
+	}
+	if len(bundles) > maxBundles {
+		bundles = bundles[:maxBundles]
+	}
+
+	var endpoints []types.Endpoint
+	for _, bundle := range bundles {
+		resp, err := c.Get(ctx, bundle)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		for _, found := range extractEndpoints(resp.Body) {
+			url := found
+			if strings.HasPrefix(found, "/") {
+				url = base + found
+			}
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        url,
+				Method:     "GET",
+				Protocol:   types.ProtocolHTTP,
+				Source:     "wasm",
+				Confidence: types.ConfidenceLow,
+				Metadata:   map[string]string{"bundle": bundle},
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// extractEndpoints runs a strings pass over the bundle's data sections and
+// collects URL-shaped and API-path-shaped entries.
+func extractEndpoints(data []byte) []string {
+	seen := map[string]bool{}
+	var found []string
+	add := func(s string) {
+		s = strings.TrimRight(s, `",');`)
+		if s != "" && !seen[s] {
+			seen[s] = true
+			found = append(found, s)
+		}
+	}
+	for _, run := range printableRuns(data, 8) {
+		for _, u := range urlRe.FindAllString(run, -1) {
+			add(u)
+		}
+		for _, path := range apiPathRe.FindAllString(run, -1) {
+			if strings.Contains(path[1:], "/") || path == "/graphql" {
+				add(path)
+			}
+		}
+	}
+	return found
+}
+
+// printableRuns returns the printable-ASCII runs of at least minLen bytes,
+// the same extraction strings(1) performs.
+func printableRuns(data []byte, minLen int) []string {
+	var runs []string
+	start := -1
+	for i, b := range data {
+		if b >= 0x20 && b < 0x7f {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && i-start >= minLen {
+			runs = append(runs, string(data[start:i]))
+		}
+		start = -1
+	}
+	if start >= 0 && len(data)-start >= minLen {
+		runs = append(runs, string(data[start:]))
+	}
+	return runs
+}
+
+// resolveRef turns a .wasm reference into an absolute URL on the target;
+// references to other hosts are out of scope.
+func resolveRef(base, ref string) string {
+	switch {
+	case strings.HasPrefix(ref, base):
+		return ref
+	case strings.HasPrefix(ref, "http://"), strings.HasPrefix(ref, "https://"), strings.HasPrefix(ref, "//"):
+		return ""
+	case strings.HasPrefix(ref, "/"):
+		return base + ref
+	default:
+		return base + "/" + ref
+	}
+}
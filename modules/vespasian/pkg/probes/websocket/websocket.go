@@ -0,0 +1,160 @@
+// Package websocket discovers WebSocket endpoints at their conventional
+// paths, records the subprotocol the server negotiates, and classifies
+// the framework behind the socket (socket.io, SockJS, Action Cable,
+// SignalR) so a tester knows which client library to reach for.
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// wsPaths are the locations WebSocket endpoints are commonly served from.
+var wsPaths = knowledge.WebSocketPaths
+
+// offeredProtocols are sent in the upgrade attempt; a server that picks
+// one reveals what it speaks without a full handshake.
+const offeredProtocols = "graphql-transport-ws, graphql-ws, mqtt, wamp.2.json, actioncable-v1-json"
+
+// Probe attempts WebSocket upgrades and framework handshakes.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "websocket" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	for _, path := range wsPaths {
+		// Framework handshakes first: socket.io and SockJS answer plain
+		// HTTP requests and identify themselves more precisely than an
+		// upgrade attempt can.
+		if metadata := frameworkHandshake(ctx, c, base, path); metadata != nil {
+			endpoints = append(endpoints, types.Endpoint{
+				URL:        base + path,
+				Method:     "GET",
+				Protocol:   types.ProtocolWebSocket,
+				Source:     "websocket",
+				Confidence: types.ConfidenceHigh,
+				Metadata:   metadata,
+			})
+			continue
+		}
+
+		metadata, confirmed := attemptUpgrade(ctx, c, base+path)
+		if !confirmed {
+			continue
+		}
+		if strings.Contains(path, "cable") {
+			metadata["framework"] = "actioncable"
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        base + path,
+			Method:     "GET",
+			Protocol:   types.ProtocolWebSocket,
+			Source:     "websocket",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+	}
+	return endpoints, nil
+}
+
+// attemptUpgrade sends a WebSocket upgrade with a set of offered
+// subprotocols. 101 confirms outright; 400/426 with WebSocket response
+// headers confirms a socket endpoint short of a full handshake.
+func attemptUpgrade(ctx context.Context, c *client.Client, url string) (map[string]string, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dmVzcGFzaWFuLXByb2JlLg==")
+	req.Header.Set("Sec-WebSocket-Protocol", offeredProtocols)
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, false
+	}
+
+	confirmed := false
+	switch resp.StatusCode {
+	case http.StatusSwitchingProtocols:
+		confirmed = true
+	case http.StatusBadRequest, http.StatusUpgradeRequired:
+		confirmed = resp.Header.Get("Sec-WebSocket-Protocol") != "" ||
+			resp.Header.Get("Sec-WebSocket-Version") != "" ||
+			resp.Header.Get("Sec-WebSocket-Accept") != ""
+	}
+	if !confirmed {
+		return nil, false
+	}
+	metadata := map[string]string{}
+	if negotiated := resp.Header.Get("Sec-WebSocket-Protocol"); negotiated != "" {
+		metadata["subprotocol"] = negotiated
+	}
+	return metadata, true
+}
+
+// frameworkHandshake sends the benign initial request socket.io and
+// SockJS endpoints answer over plain HTTP and classifies the framework.
+func frameworkHandshake(ctx context.Context, c *client.Client, base, path string) map[string]string {
+	switch {
+	case strings.Contains(path, "socket.io"):
+		resp, err := c.Get(ctx, base+path+"?EIO=4&transport=polling")
+		if err != nil || resp.StatusCode != 200 {
+			return nil
+		}
+		body := string(resp.Body)
+		if !strings.Contains(body, `"sid"`) {
+			return nil
+		}
+		metadata := map[string]string{"framework": "socket.io"}
+		if strings.Contains(body, `"websocket"`) {
+			metadata["upgrades"] = "websocket"
+		}
+		return metadata
+	case strings.Contains(path, "sockjs"):
+		resp, err := c.Get(ctx, base+path)
+		if err != nil || resp.StatusCode != 200 {
+			return nil
+		}
+		body := string(resp.Body)
+		if !strings.Contains(body, `"entropy"`) {
+			return nil
+		}
+		metadata := map[string]string{"framework": "sockjs"}
+		if strings.Contains(body, `"websocket":true`) {
+			metadata["upgrades"] = "websocket"
+		}
+		return metadata
+	case strings.Contains(path, "hub"):
+		// SignalR negotiates over POST before any socket is opened.
+		resp, err := c.Post(ctx, base+path+"/negotiate", "application/json", strings.NewReader("{}"))
+		if err != nil || resp.StatusCode != 200 {
+			return nil
+		}
+		if !strings.Contains(string(resp.Body), "availableTransports") {
+			return nil
+		}
+		return map[string]string{"framework": "signalr"}
+	}
+	return nil
+}
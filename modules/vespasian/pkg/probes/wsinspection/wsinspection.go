@@ -0,0 +1,171 @@
+// Package wsinspection discovers SOAP services through legacy discovery
+// documents: WS-Inspection (.wsil), .NET DISCO, and UDDI registry pages.
+// Enterprises rarely remove these registries, and a forgotten one often
+// lists every internal service; each referenced WSDL is chained into the
+// WSDL parser so the services come back as concrete operations.
+package wsinspection
+
+import (
+	"context"
+	"encoding/xml"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/knowledge"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/probes/soap"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+func init() {
+	probe.Register(&Probe{})
+}
+
+// discoveryPaths are where the legacy discovery documents live.
+var discoveryPaths = []string{
+	"/inspection.wsil",
+	"/default.disco",
+	"/disco.aspx",
+	"/uddi",
+	"/uddi/inquire",
+}
+
+// wsdlURLRe harvests WSDL references from documents the structured
+// parsers do not cover, UDDI HTML pages in particular.
+var wsdlURLRe = regexp.MustCompile(`(?i)["'>]([^"'<>\s]+(?:\.wsdl|\?wsdl))["'<]`)
+
+// maxWSDLFetches caps how many referenced WSDLs one target may send the
+// probe chasing.
+const maxWSDLFetches = 10
+
+// Probe parses discovery documents and chains into the WSDL parser.
+type Probe struct{}
+
+// Name implements probe.Probe.
+func (*Probe) Name() string { return "ws-inspection" }
+
+// Accepts implements probe.Probe.
+func (*Probe) Accepts(target types.Target) bool {
+	return knowledge.AcceptsTarget(target)
+}
+
+// Run implements probe.Probe.
+func (p *Probe) Run(ctx context.Context, target types.Target, c *client.Client) ([]types.Endpoint, error) {
+	base := target.BaseURL()
+	var endpoints []types.Endpoint
+	seen := map[string]bool{}
+	budget := maxWSDLFetches
+
+	for _, path := range discoveryPaths {
+		docURL := base + path
+		resp, err := c.Get(ctx, docURL)
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+		for _, ref := range wsdlRefs(resp.Body) {
+			wsdlURL := resolve(docURL, ref)
+			if wsdlURL == "" || seen[wsdlURL] || budget == 0 {
+				continue
+			}
+			seen[wsdlURL] = true
+			budget--
+			endpoints = append(endpoints, fetchWSDL(ctx, c, docURL, wsdlURL)...)
+		}
+	}
+	return endpoints, nil
+}
+
+// inspectionDoc covers both WS-Inspection and DISCO: a .wsil lists
+// service descriptions, a .disco lists contract references. Field names
+// are unqualified so either namespace matches.
+type inspectionDoc struct {
+	Descriptions []struct {
+		Location string `xml:"location,attr"`
+	} `xml:"service>description"`
+	ContractRefs []struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"contractRef"`
+}
+
+// wsdlRefs extracts WSDL references from a discovery document, falling
+// back to a URL harvest for registry pages that are plain HTML.
+func wsdlRefs(body []byte) []string {
+	seen := map[string]bool{}
+	var refs []string
+	add := func(ref string) {
+		if ref != "" && !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	var doc inspectionDoc
+	if err := xml.Unmarshal(body, &doc); err == nil {
+		for _, description := range doc.Descriptions {
+			add(description.Location)
+		}
+		for _, contract := range doc.ContractRefs {
+			add(contract.Ref)
+		}
+	}
+	for _, match := range wsdlURLRe.FindAllSubmatch(body, -1) {
+		add(string(match[1]))
+	}
+	return refs
+}
+
+// fetchWSDL retrieves one referenced WSDL and turns its operations into
+// endpoints, the same shape the soap probe emits.
+func fetchWSDL(ctx context.Context, c *client.Client, registry, wsdlURL string) []types.Endpoint {
+	resp, err := c.Get(ctx, wsdlURL)
+	if err != nil || resp.StatusCode != 200 {
+		return nil
+	}
+	if !strings.Contains(string(resp.Body), "definitions") {
+		return nil
+	}
+	operations, err := soap.ParseWSDL(resp.Body)
+	if err != nil {
+		return nil
+	}
+	var endpoints []types.Endpoint
+	for _, op := range operations {
+		address := op.Address
+		if address == "" {
+			address = strings.SplitN(wsdlURL, "?", 2)[0]
+		}
+		metadata := map[string]string{
+			"wsdl":        wsdlURL,
+			"registry":    registry,
+			"service":     op.Service,
+			"operation":   op.Name,
+			"soap_action": op.SOAPAction,
+		}
+		if op.Namespace != "" {
+			metadata["namespace"] = op.Namespace
+		}
+		endpoints = append(endpoints, types.Endpoint{
+			URL:        address,
+			Method:     "POST",
+			Protocol:   types.ProtocolSOAP,
+			Source:     "ws-inspection",
+			Confidence: types.ConfidenceHigh,
+			Metadata:   metadata,
+		})
+	}
+	return endpoints
+}
+
+// resolve makes a reference absolute against the document it came from.
+func resolve(docURL, ref string) string {
+	base, err := url.Parse(docURL)
+	if err != nil {
+		return ""
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return ""
+	}
+	return resolved.String()
+}
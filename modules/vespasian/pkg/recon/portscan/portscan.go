@@ -0,0 +1,109 @@
+// Package portscan expands bare-host targets into per-port targets by
+// TCP-scanning a port list, so a scan can start from a hostname without
+// a prior nmap run feeding it host:port pairs. Scans are plain connect
+// scans — completing the handshake needs no raw-socket privileges and
+// is indistinguishable from a real client to the service — rather than
+// SYN scans, which would require root and a packet-crafting stack.
+package portscan
+
+import (
+	"context"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// DefaultPorts are the ports API surfaces most commonly bind: the web
+// defaults plus the development-framework and gateway ports from the
+// api-extended profile.
+var DefaultPorts = []int{
+	80, 443, 3000, 4000, 5000, 8000,
+	8080, 8081, 8443, 8888, 9000, 9090, 9443,
+}
+
+// Options configures a port scan.
+type Options struct {
+	// Ports to check; defaults to DefaultPorts.
+	Ports []int
+	// Timeout bounds each connection attempt; defaults to 2s.
+	Timeout time.Duration
+	// Concurrency bounds parallel connection attempts per host;
+	// defaults to 20.
+	Concurrency int
+}
+
+func (o Options) withDefaults() Options {
+	if len(o.Ports) == 0 {
+		o.Ports = DefaultPorts
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 2 * time.Second
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = 20
+	}
+	return o
+}
+
+// Expand replaces each bare-host target with one target per open port.
+// Targets that already carry a port, scheme, or socket pass through
+// untouched, as does a bare host on which nothing answered — the port
+// profiles still accept portless targets, so passive probes keep
+// working against it.
+func Expand(ctx context.Context, targets []types.Target, opts Options) []types.Target {
+	expanded := make([]types.Target, 0, len(targets))
+	for _, target := range targets {
+		if target.Port != 0 || target.Socket != "" || target.Scheme != "" {
+			expanded = append(expanded, target)
+			continue
+		}
+		open := Scan(ctx, target.Host, opts)
+		if len(open) == 0 {
+			expanded = append(expanded, target)
+			continue
+		}
+		log.Printf("portscan: %s: %d open of %d scanned ports", target.Host, len(open), len(opts.withDefaults().Ports))
+		for _, port := range open {
+			expanded = append(expanded, types.Target{Host: target.Host, Port: port})
+		}
+	}
+	return expanded
+}
+
+// Scan connect-scans host and returns the open ports in ascending
+// order.
+func Scan(ctx context.Context, host string, opts Options) []int {
+	opts = opts.withDefaults()
+	sem := make(chan struct{}, opts.Concurrency)
+	var (
+		mu   sync.Mutex
+		open []int
+		wg   sync.WaitGroup
+	)
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	for _, port := range opts.Ports {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(port int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			address := net.JoinHostPort(host, strconv.Itoa(port))
+			conn, err := dialer.DialContext(ctx, "tcp", address)
+			if err != nil {
+				return
+			}
+			conn.Close()
+			mu.Lock()
+			open = append(open, port)
+			mu.Unlock()
+		}(port)
+	}
+	wg.Wait()
+	sort.Ints(open)
+	return open
+}
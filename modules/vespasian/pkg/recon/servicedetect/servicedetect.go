@@ -0,0 +1,94 @@
+// Package servicedetect performs the pre-flight banner check that tells
+// probes what a port actually speaks before port lists guess. One TLS
+// handshake attempt (with ALPN) plus one minimal HTTP request per target
+// is enough to route an API on 9443 or 8000 into the HTTP probe set
+// instead of skipping it for having an unconventional port number.
+package servicedetect
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// concurrency bounds parallel detection dials.
+const concurrency = 10
+
+// DetectAll runs service detection against every target with an
+// explicit port and stores the verdict on it. Targets without a port,
+// or reached through a Unix socket, are left alone — the port profiles
+// already accept them.
+func DetectAll(ctx context.Context, targets []types.Target, timeout time.Duration) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range targets {
+		if targets[i].Port == 0 || targets[i].Socket != "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(target *types.Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			target.Service = Detect(ctx, target.Address(), timeout)
+		}(&targets[i])
+	}
+	wg.Wait()
+}
+
+// Detect probes one host:port address. A nil result means nothing
+// answered at all; probes then fall back to their port lists.
+func Detect(ctx context.Context, address string, timeout time.Duration) *types.Service {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	// TLS first: a successful handshake settles the scheme and, via
+	// ALPN, often the protocol too.
+	host, _, _ := net.SplitHostPort(address)
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config: &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"h2", "http/1.1"},
+		},
+	}
+	if conn, err := dialer.DialContext(ctx, "tcp", address); err == nil {
+		service := &types.Service{TLS: true}
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			service.ALPN = tlsConn.ConnectionState().NegotiatedProtocol
+		}
+		// h2 is HTTP by definition; otherwise ask.
+		service.HTTP = service.ALPN == "h2" || speaksHTTP(conn, host, timeout)
+		conn.Close()
+		return service
+	}
+
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	return &types.Service{HTTP: speaksHTTP(conn, host, timeout)}
+}
+
+// speaksHTTP sends a minimal HEAD request over an established
+// connection and reports whether an HTTP status line came back.
+func speaksHTTP(conn net.Conn, host string, timeout time.Duration) bool {
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write([]byte("HEAD / HTTP/1.1\r\nHost: " + host + "\r\nConnection: close\r\n\r\n")); err != nil {
+		return false
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return false
+	}
+	return strings.HasPrefix(line, "HTTP/")
+}
@@ -0,0 +1,51 @@
+package subdomains
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+)
+
+// CrtShSource enumerates subdomains from certificate transparency logs via
+// the crt.sh JSON API.
+type CrtShSource struct {
+	// BaseURL overrides the crt.sh endpoint, mainly for tests and
+	// self-hosted mirrors.
+	BaseURL string
+}
+
+// Name implements Source.
+func (*CrtShSource) Name() string { return "crtsh" }
+
+// Enumerate implements Source.
+func (s *CrtShSource) Enumerate(ctx context.Context, domain string, c *client.Client) ([]string, error) {
+	base := s.BaseURL
+	if base == "" {
+		base = "https://crt.sh"
+	}
+	resp, err := c.Get(ctx, base+"/?q="+url.QueryEscape("%."+domain)+"&output=json")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("crtsh: unexpected status %d", resp.StatusCode)
+	}
+
+	var records []struct {
+		NameValue string `json:"name_value"`
+	}
+	if err := json.Unmarshal(resp.Body, &records); err != nil {
+		return nil, fmt.Errorf("crtsh: decoding response: %w", err)
+	}
+
+	var hosts []string
+	for _, record := range records {
+		// name_value may hold several SAN entries separated by newlines.
+		hosts = append(hosts, strings.Split(record.NameValue, "\n")...)
+	}
+	return hosts, nil
+}
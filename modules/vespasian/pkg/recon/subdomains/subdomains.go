@@ -0,0 +1,89 @@
+// Package subdomains performs passive subdomain enumeration so a scan can
+// start from an apex domain instead of a host list. Candidate hosts come
+// from certificate transparency logs and any additional configured passive
+// sources, and are fed into the discovery pipeline as new targets.
+package subdomains
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Source is a single passive enumeration data source.
+type Source interface {
+	// Name identifies the source in logs.
+	Name() string
+	// Enumerate returns candidate hostnames under domain. Results may
+	// contain duplicates and out-of-scope names; the enumerator filters.
+	Enumerate(ctx context.Context, domain string, c *client.Client) ([]string, error)
+}
+
+// Enumerator queries passive sources and merges their results.
+type Enumerator struct {
+	client  *client.Client
+	sources []Source
+}
+
+// New returns an Enumerator over the given sources, defaulting to
+// certificate transparency when none are supplied.
+func New(c *client.Client, sources ...Source) *Enumerator {
+	if len(sources) == 0 {
+		sources = []Source{&CrtShSource{}}
+	}
+	return &Enumerator{client: c, sources: sources}
+}
+
+// Enumerate returns deduplicated targets for every candidate host found
+// under domain. Source failures are logged and skipped so one offline
+// source does not abort enumeration.
+func (e *Enumerator) Enumerate(ctx context.Context, domain string) ([]types.Target, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	seen := map[string]bool{}
+	for _, source := range e.sources {
+		hosts, err := source.Enumerate(ctx, domain, e.client)
+		if err != nil {
+			log.Printf("subdomains: source %s: %v", source.Name(), err)
+			continue
+		}
+		for _, host := range hosts {
+			host = normalize(host)
+			if host == "" || !inScope(host, domain) {
+				continue
+			}
+			seen[host] = true
+		}
+	}
+
+	hosts := make([]string, 0, len(seen))
+	for host := range seen {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	targets := make([]types.Target, 0, len(hosts))
+	for _, host := range hosts {
+		targets = append(targets, types.Target{Host: host})
+	}
+	return targets, nil
+}
+
+// normalize lowercases a candidate and strips wildcard labels and trailing
+// dots; it returns "" for names that cannot be scanned directly.
+func normalize(host string) string {
+	host = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(host, ".")))
+	host = strings.TrimPrefix(host, "*.")
+	if host == "" || strings.ContainsAny(host, " \t*") {
+		return ""
+	}
+	return host
+}
+
+// inScope reports whether host is domain itself or a subdomain of it.
+func inScope(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
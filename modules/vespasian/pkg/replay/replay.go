@@ -0,0 +1,112 @@
+// Package replay provides a canned-response transport and a fake clock for
+// running the scan pipeline deterministically: probe behavior can be
+// asserted against recorded traffic without touching the network or real
+// time.
+package replay
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Exchange is one recorded response, keyed by method and URL.
+type Exchange struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Transport serves recorded exchanges in place of the network. Requests
+// without a recording get a 404 with an empty body, which probes already
+// treat as "not here". Every request is counted, so tests can assert how
+// many attempts a retry policy actually produced.
+type Transport struct {
+	mu        sync.Mutex
+	exchanges map[string][]Exchange
+	served    map[string]int
+}
+
+// NewTransport returns an empty Transport ready for Record calls.
+func NewTransport() *Transport {
+	return &Transport{exchanges: map[string][]Exchange{}, served: map[string]int{}}
+}
+
+// Record registers the response to serve for method and url.
+func (t *Transport) Record(method, url string, exchange Exchange) {
+	t.RecordSequence(method, url, exchange)
+}
+
+// RecordSequence registers successive responses for method and url: the
+// first request gets the first exchange, and so on, with the last one
+// served from then on. A 503 followed by a 200 is how a retry test makes
+// the target recover.
+func (t *Transport) RecordSequence(method, url string, exchanges ...Exchange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exchanges[method+" "+url] = exchanges
+}
+
+// Requests returns how many requests for method and url the transport
+// has served, recorded or not.
+func (t *Transport) Requests(method, url string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.served[method+" "+url]
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	t.mu.Lock()
+	sequence := t.exchanges[key]
+	attempt := t.served[key]
+	t.served[key]++
+	t.mu.Unlock()
+	exchange := Exchange{StatusCode: http.StatusNotFound}
+	if len(sequence) > 0 {
+		exchange = sequence[min(attempt, len(sequence)-1)]
+	}
+	header := exchange.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: exchange.StatusCode,
+		Status:     http.StatusText(exchange.StatusCode),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(exchange.Body)),
+		Request:    req,
+	}, nil
+}
+
+// Clock is a manually advanced clock for deterministic timestamps. Its
+// Now method plugs into scan.Options.Now.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a Clock frozen at start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
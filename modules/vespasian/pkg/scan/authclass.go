@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// classifyAuth probes each HTTP-family endpoint with an unauthenticated
+// GET and records whether authentication is required. Knowing which parts
+// of a surface are reachable anonymously is usually the first question a
+// reviewer asks of an inventory.
+func (s *Scanner) classifyAuth(ctx context.Context, endpoints []types.Endpoint) {
+	// Classify each URL once even when it appears with several methods.
+	classified := map[string]map[string]string{}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if endpoint.Protocol != types.ProtocolHTTP && endpoint.Protocol != types.ProtocolGraphQL {
+			continue
+		}
+		verdict, ok := classified[endpoint.URL]
+		if !ok {
+			verdict = s.classifyURL(ctx, endpoint.URL)
+			classified[endpoint.URL] = verdict
+		}
+		if verdict == nil {
+			continue
+		}
+		if endpoint.Metadata == nil {
+			endpoint.Metadata = map[string]string{}
+		}
+		for key, value := range verdict {
+			endpoint.Metadata[key] = value
+		}
+	}
+}
+
+// classifyURL returns auth metadata for a URL, or nil when the request
+// could not be completed.
+func (s *Scanner) classifyURL(ctx context.Context, url string) map[string]string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil
+	}
+	verdict := map[string]string{}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		verdict["auth"] = "required"
+		if challenge := resp.Header.Get("WWW-Authenticate"); challenge != "" {
+			verdict["auth_scheme"] = strings.SplitN(strings.TrimSpace(challenge), " ", 2)[0]
+		}
+	case resp.StatusCode == http.StatusForbidden:
+		verdict["auth"] = "forbidden"
+	case resp.StatusCode >= 200 && resp.StatusCode < 400:
+		verdict["auth"] = "open"
+	default:
+		verdict["auth"] = "unknown"
+	}
+	return verdict
+}
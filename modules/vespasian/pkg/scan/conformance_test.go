@@ -0,0 +1,102 @@
+package scan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/internal/testserver"
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	_ "github.com/praetorian-inc/vespasian/pkg/probes"
+	"github.com/praetorian-inc/vespasian/pkg/scan"
+	"github.com/praetorian-inc/vespasian/pkg/targets"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TestConformance runs the full probe set against the simulated target
+// and asserts minimum discovery counts per probe. The minimums are
+// deliberately loose: the suite exists to catch detectors going blind
+// during refactors, not to pin exact outputs.
+func TestConformance(t *testing.T) {
+	server := testserver.New(testserver.Options{Introspection: true})
+	defer server.Close()
+
+	c := client.New(client.Options{Timeout: 5 * time.Second})
+	scanner := scan.New(scan.Options{Client: c})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	result, err := scanner.Scan(ctx, []types.Target{fixtureTarget(t, server.URL)})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, endpoint := range result.Endpoints {
+		counts[endpoint.Source]++
+	}
+	minimums := map[string]int{
+		"crawler":   2, // SPA links and bundle-mined API paths
+		"openapi":   3, // three operations in the swagger document
+		"graphql":   3, // two queries and one mutation via introspection
+		"soap":      1, // one operation bound in the WSDL
+		"websocket": 2, // socket.io and SockJS framework handshakes
+	}
+	for source, want := range minimums {
+		if counts[source] < want {
+			t.Errorf("probe %s discovered %d endpoints, want at least %d", source, counts[source], want)
+		}
+	}
+
+	// The soft-404 catch-all answers every path with 200; nothing may
+	// mistake the not-found page itself for an endpoint.
+	for _, endpoint := range result.Endpoints {
+		if endpoint.URL == server.URL+"/vespasian-err-probe-42" {
+			t.Errorf("soft-404 page reported as endpoint: %s (source %s)", endpoint.URL, endpoint.Source)
+		}
+	}
+}
+
+// TestConformanceIntrospectionDisabled hardens the fixture's GraphQL
+// endpoint and asserts the probe still recovers operations through the
+// field-suggestion fallback instead of going blind.
+func TestConformanceIntrospectionDisabled(t *testing.T) {
+	server := testserver.New(testserver.Options{Introspection: false})
+	defer server.Close()
+
+	graphql, ok := probe.Get("graphql")
+	if !ok {
+		t.Fatal("graphql probe not registered")
+	}
+	c := client.New(client.Options{Timeout: 5 * time.Second})
+	scanner := scan.New(scan.Options{Client: c, Probes: []probe.Probe{graphql}})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	result, err := scanner.Scan(ctx, []types.Target{fixtureTarget(t, server.URL)})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	suggested := 0
+	for _, endpoint := range result.Endpoints {
+		if endpoint.Metadata["origin"] == "field-suggestion" {
+			suggested++
+		}
+	}
+	// The fixture suggests three real field names per unknown-field error.
+	if suggested < 3 {
+		t.Errorf("suggestion fallback recovered %d operations, want at least 3", suggested)
+	}
+}
+
+// fixtureTarget parses the fixture server URL into a target; the
+// explicit scheme routes it into the HTTP probe set whatever random
+// port httptest picked.
+func fixtureTarget(t *testing.T, url string) types.Target {
+	t.Helper()
+	target, err := targets.Parse(url)
+	if err != nil {
+		t.Fatalf("parsing fixture URL %s: %v", url, err)
+	}
+	return target
+}
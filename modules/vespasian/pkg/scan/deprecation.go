@@ -0,0 +1,56 @@
+package scan
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// recordDeprecation inspects a verified endpoint's response headers for the
+// Deprecation and Sunset headers (RFC 8594 / draft-ietf-httpapi-deprecation)
+// and Link rel="deprecation" pointers. Deprecated-but-live endpoints are
+// worth surfacing: they frequently lag on security fixes while still being
+// reachable.
+func recordDeprecation(endpoint *types.Endpoint, header http.Header) {
+	deprecated := false
+	if v := header.Get("Deprecation"); v != "" {
+		endpoint.Metadata["deprecation"] = v
+		deprecated = true
+	}
+	if v := header.Get("Sunset"); v != "" {
+		endpoint.Metadata["sunset"] = v
+		deprecated = true
+	}
+	if link := deprecationLink(header); link != "" {
+		endpoint.Metadata["deprecation_link"] = link
+		deprecated = true
+	}
+	if deprecated {
+		endpoint.Metadata["deprecated"] = "true"
+	}
+}
+
+// deprecationLink returns the target of a Link header with
+// rel="deprecation", or "" when none is present.
+func deprecationLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, param := range parts[1:] {
+				key, rel, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				rel = strings.Trim(strings.TrimSpace(rel), `"`)
+				for _, r := range strings.Fields(rel) {
+					if strings.EqualFold(r, "deprecation") {
+						return target
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
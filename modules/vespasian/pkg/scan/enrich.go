@@ -0,0 +1,36 @@
+package scan
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// enrichEndpoints annotates every endpoint with its host's network
+// context (ASN, organization, CDN). The enricher caches per host, so a
+// large inventory costs one lookup per distinct host.
+func (s *Scanner) enrichEndpoints(ctx context.Context, endpoints []types.Endpoint) {
+	for i := range endpoints {
+		parsed, err := url.Parse(endpoints[i].URL)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+		info := s.enricher.Enrich(ctx, s.client, parsed.Hostname())
+		if info.ASN == "" && info.Org == "" && info.CDN == "" {
+			continue
+		}
+		if endpoints[i].Metadata == nil {
+			endpoints[i].Metadata = map[string]string{}
+		}
+		if info.ASN != "" {
+			endpoints[i].Metadata["asn"] = info.ASN
+		}
+		if info.Org != "" {
+			endpoints[i].Metadata["org"] = info.Org
+		}
+		if info.CDN != "" {
+			endpoints[i].Metadata["cdn"] = info.CDN
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package scan
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// probeStats tallies probe executions and failures per target while the
+// worker pool runs. remaining counts down the target's scheduled probes so
+// the scanner knows when its last one finishes.
+type probeStats struct {
+	probes    int
+	errors    int
+	endpoints int
+	remaining int
+}
+
+// measureHealth issues one instrumented request per target and combines
+// the observed latency and TLS handshake time with the probe error tallies
+// into the per-target health summary.
+func (s *Scanner) measureHealth(ctx context.Context, targets []types.Target, stats map[string]*probeStats) []types.TargetHealth {
+	health := make([]types.TargetHealth, 0, len(targets))
+	for _, target := range targets {
+		entry := types.TargetHealth{Target: target.Address()}
+		if st := stats[target.Address()]; st != nil {
+			entry.Probes = st.probes
+			entry.Errors = st.errors
+			if st.probes > 0 {
+				entry.ErrorRate = float64(st.errors) / float64(st.probes)
+			}
+		}
+
+		var tlsStart, tlsDone time.Time
+		trace := &httptrace.ClientTrace{
+			TLSHandshakeStart: func() { tlsStart = time.Now() },
+			TLSHandshakeDone:  func(_ tls.ConnectionState, _ error) { tlsDone = time.Now() },
+		}
+		req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, target.BaseURL()+"/", nil)
+		if err == nil {
+			started := time.Now()
+			if _, err := s.client.Do(req); err == nil {
+				entry.Reachable = true
+				entry.Latency = time.Since(started).Round(time.Millisecond).String()
+				if !tlsStart.IsZero() && tlsDone.After(tlsStart) {
+					entry.TLSHandshake = tlsDone.Sub(tlsStart).Round(time.Millisecond).String()
+				}
+			}
+		}
+		health = append(health, entry)
+	}
+	return health
+}
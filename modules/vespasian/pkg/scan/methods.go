@@ -0,0 +1,124 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// discoverMethods widens the verb picture for crawler-discovered paths.
+// The crawler only ever GETs, so its endpoints say nothing about whether
+// a path also accepts writes. For each crawler URL with only GET
+// recorded, this pass sends one OPTIONS (whose Allow header is the
+// server's own verb list) plus a HEAD and an empty-body POST, and
+// annotates the endpoint with what answered. Verbs that respond 401,
+// 403, or 405 are present but disallowed — routed, just gated — which
+// is exactly the kind of latent surface worth recording.
+func (s *Scanner) discoverMethods(ctx context.Context, endpoints []types.Endpoint) {
+	// Collect the methods already recorded per URL across all sources,
+	// so spec-derived verb coverage suppresses the extra requests.
+	recorded := map[string]map[string]bool{}
+	for _, endpoint := range endpoints {
+		if endpoint.Protocol != types.ProtocolHTTP {
+			continue
+		}
+		method := endpoint.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		if recorded[endpoint.URL] == nil {
+			recorded[endpoint.URL] = map[string]bool{}
+		}
+		recorded[endpoint.URL][method] = true
+	}
+
+	probed := map[string]map[string]string{}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if endpoint.Source != "crawler" || endpoint.Protocol != types.ProtocolHTTP {
+			continue
+		}
+		methods := recorded[endpoint.URL]
+		if len(methods) != 1 || !methods[http.MethodGet] {
+			continue
+		}
+		verdict, ok := probed[endpoint.URL]
+		if !ok {
+			verdict = s.probeMethods(ctx, endpoint.URL)
+			probed[endpoint.URL] = verdict
+		}
+		if len(verdict) == 0 {
+			continue
+		}
+		if endpoint.Metadata == nil {
+			endpoint.Metadata = map[string]string{}
+		}
+		for key, value := range verdict {
+			endpoint.Metadata[key] = value
+		}
+	}
+}
+
+// probeMethods interrogates one URL: the Allow header from OPTIONS, then
+// HEAD and an empty-body POST as lightweight liveness probes. Both probe
+// verbs are harmless — HEAD by definition, an empty POST because any
+// handler doing work on it would fail validation first.
+func (s *Scanner) probeMethods(ctx context.Context, url string) map[string]string {
+	verdict := map[string]string{}
+	if allow := s.optionsAllow(ctx, url); len(allow) > 0 {
+		verdict["allow"] = strings.Join(allow, ", ")
+	}
+
+	var present, denied []string
+	for _, method := range []string{http.MethodHead, http.MethodPost} {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			continue
+		}
+		switch {
+		case resp.StatusCode < 400:
+			present = append(present, method)
+		case resp.StatusCode == http.StatusUnauthorized,
+			resp.StatusCode == http.StatusForbidden,
+			resp.StatusCode == http.StatusMethodNotAllowed:
+			denied = append(denied, method)
+		}
+	}
+	if len(present) > 0 {
+		verdict["methods"] = strings.Join(present, ", ")
+	}
+	if len(denied) > 0 {
+		verdict["methods_denied"] = strings.Join(denied, ", ")
+	}
+	return verdict
+}
+
+// optionsAllow returns the verbs an OPTIONS request's Allow header
+// advertises, upper-cased and deduplicated in server order.
+func (s *Scanner) optionsAllow(ctx context.Context, url string) []string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, url, nil)
+	if err != nil {
+		return nil
+	}
+	resp, err := s.client.Do(req)
+	if err != nil || resp.StatusCode >= 500 {
+		return nil
+	}
+	var allow []string
+	seen := map[string]bool{}
+	for _, field := range strings.Split(resp.Header.Get("Allow"), ",") {
+		method := strings.ToUpper(strings.TrimSpace(field))
+		if method == "" || seen[method] {
+			continue
+		}
+		seen[method] = true
+		allow = append(allow, method)
+	}
+	return allow
+}
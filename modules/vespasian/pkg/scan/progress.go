@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Progress receives scan progress updates. Implementations must be safe
+// for concurrent use; workers report from multiple goroutines.
+type Progress interface {
+	// Start announces the total number of probe executions scheduled.
+	Start(totalJobs int)
+	// JobDone reports one finished probe execution and how many
+	// endpoints it produced.
+	JobDone(probe, target string, endpoints int)
+	// Finish marks the end of the scan.
+	Finish()
+}
+
+// TerminalProgress renders a single live status line, redrawn in place,
+// suitable for interactive runs where results go to a file. Updates are
+// throttled so fast scans do not flood the terminal.
+type TerminalProgress struct {
+	W io.Writer
+
+	mu        sync.Mutex
+	total     int
+	done      int
+	endpoints int
+	lastDraw  time.Time
+}
+
+// Start implements Progress.
+func (p *TerminalProgress) Start(totalJobs int) {
+	p.mu.Lock()
+	p.total = totalJobs
+	p.mu.Unlock()
+	p.draw(true)
+}
+
+// JobDone implements Progress.
+func (p *TerminalProgress) JobDone(probe, target string, endpoints int) {
+	p.mu.Lock()
+	p.done++
+	p.endpoints += endpoints
+	p.mu.Unlock()
+	p.draw(false)
+}
+
+// Finish implements Progress.
+func (p *TerminalProgress) Finish() {
+	p.draw(true)
+	fmt.Fprintln(p.W)
+}
+
+func (p *TerminalProgress) draw(force bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !force && time.Since(p.lastDraw) < 100*time.Millisecond {
+		return
+	}
+	p.lastDraw = time.Now()
+	fmt.Fprintf(p.W, "\r\033[Kprobes %d/%d | endpoints %d", p.done, p.total, p.endpoints)
+}
@@ -0,0 +1,43 @@
+package scan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/replay"
+	"github.com/praetorian-inc/vespasian/pkg/scan"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TestReplayClockTimestamps runs scans entirely against the replay
+// harness and asserts the result timestamps come from the injected
+// clock, so CI can pin scan output byte-for-byte across runs.
+func TestReplayClockTimestamps(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := replay.NewClock(start)
+	c := client.New(client.Options{Transport: replay.NewTransport()})
+	// An empty (non-nil) probe set keeps the run to the orchestrator
+	// itself; this test is about the clock, not discovery.
+	scanner := scan.New(scan.Options{Client: c, Probes: []probe.Probe{}, Now: clock.Now})
+
+	result, err := scanner.Scan(context.Background(), []types.Target{{Host: "target.invalid", Port: 443}})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if !result.StartedAt.Time.Equal(start) || !result.FinishedAt.Time.Equal(start) {
+		t.Errorf("scan ran %s to %s, want both frozen at %s",
+			result.StartedAt.Time, result.FinishedAt.Time, start)
+	}
+
+	clock.Advance(42 * time.Second)
+	result, err = scanner.Scan(context.Background(), []types.Target{{Host: "target.invalid", Port: 443}})
+	if err != nil {
+		t.Fatalf("second scan failed: %v", err)
+	}
+	if want := start.Add(42 * time.Second); !result.StartedAt.Time.Equal(want) {
+		t.Errorf("second scan started at %s, want the advanced clock %s", result.StartedAt.Time, want)
+	}
+}
@@ -0,0 +1,78 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// sample caps how many endpoints any single probe contributes to the main
+// result. When a probe exceeds the threshold — a runaway crawl, a huge
+// spec — its full set is written to an NDJSON side file and only the first
+// threshold endpoints stay in the report, each annotated with the total
+// and where the rest went. Endpoints are already sorted, so the sample is
+// deterministic.
+func (s *Scanner) sample(endpoints []types.Endpoint) ([]types.Endpoint, error) {
+	counts := map[string]int{}
+	for _, endpoint := range endpoints {
+		counts[endpoint.Source]++
+	}
+
+	oversized := map[string]string{}
+	for source, count := range counts {
+		if count <= s.sampleThreshold {
+			continue
+		}
+		path := filepath.Join(s.sampleDir, "vespasian-"+source+".ndjson")
+		if err := writeDetail(path, source, endpoints); err != nil {
+			return nil, err
+		}
+		oversized[source] = path
+	}
+	if len(oversized) == 0 {
+		return endpoints, nil
+	}
+
+	kept := endpoints[:0]
+	taken := map[string]int{}
+	for _, endpoint := range endpoints {
+		detail, sampled := oversized[endpoint.Source]
+		if !sampled {
+			kept = append(kept, endpoint)
+			continue
+		}
+		if taken[endpoint.Source] >= s.sampleThreshold {
+			continue
+		}
+		taken[endpoint.Source]++
+		if endpoint.Metadata == nil {
+			endpoint.Metadata = map[string]string{}
+		}
+		endpoint.Metadata["sampled"] = fmt.Sprintf("%d of %d", s.sampleThreshold, counts[endpoint.Source])
+		endpoint.Metadata["detail_file"] = detail
+		kept = append(kept, endpoint)
+	}
+	return kept, nil
+}
+
+// writeDetail dumps every endpoint from source to path as NDJSON.
+func writeDetail(path, source string, endpoints []types.Endpoint) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, endpoint := range endpoints {
+		if endpoint.Source != source {
+			continue
+		}
+		if err := enc.Encode(endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,439 @@
+// Package scan orchestrates probe execution across targets and assembles
+// the final scan result.
+package scan
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/artifact"
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/enrich"
+	"github.com/praetorian-inc/vespasian/pkg/inventory"
+	"github.com/praetorian-inc/vespasian/pkg/metrics"
+	"github.com/praetorian-inc/vespasian/pkg/policy"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/tracing"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Scan instruments, scraped via pkg/metrics when a metrics address is
+// configured.
+var (
+	probeRuns = metrics.NewCounter("vespasian_probe_runs_total",
+		"Probe executions, by probe and outcome.", "probe", "outcome")
+	probeSeconds = metrics.NewCounter("vespasian_probe_seconds_total",
+		"Wall-clock seconds spent in probe runs, by probe.", "probe")
+	endpointsDiscovered = metrics.NewCounter("vespasian_endpoints_discovered_total",
+		"Endpoints entering deduplication.")
+	endpointsDeduped = metrics.NewCounter("vespasian_endpoints_deduped_total",
+		"Endpoints removed as duplicates.")
+)
+
+// Options configures a Scanner.
+type Options struct {
+	// Client is the HTTP client shared by all probes. Required.
+	Client *client.Client
+	// Probes to run; defaults to every registered probe.
+	Probes []probe.Probe
+	// Concurrency bounds parallel probe executions; defaults to 10.
+	Concurrency int
+	// Store, when non-nil, captures evidence artifacts and receives the
+	// chain-of-custody manifest when the scan finishes.
+	Store *artifact.Store
+	// MinConfidence drops endpoints scored below it; zero keeps all.
+	MinConfidence float64
+	// TracePath, when set, writes a JSON execution trace of every probe
+	// run (timing, result counts, errors) to this file after the scan.
+	TracePath string
+	// MethodPolicy selects which HTTP methods endpoint verification may
+	// send; the default, PolicyNone, skips verification.
+	MethodPolicy MethodPolicy
+	// ClassifyAuth probes each endpoint anonymously after discovery and
+	// tags whether authentication is required.
+	ClassifyAuth bool
+	// DiscoverMethods sends OPTIONS plus lightweight HEAD/POST probes to
+	// crawler-discovered URLs that only have GET recorded and annotates
+	// them with the verbs the server admits to.
+	DiscoverMethods bool
+	// VersionSweep probes the numerically adjacent versions of every
+	// versioned endpoint and reports the ones that still answer.
+	VersionSweep bool
+	// Progress, when non-nil, receives live scan progress updates.
+	Progress Progress
+	// Now supplies the scan's clock; defaults to time.Now. Injecting a
+	// fake clock makes result and trace timestamps deterministic.
+	Now func() time.Time
+	// Dedupe collapses endpoints differing only in path parameter values
+	// (/users/123, /users/456 → /users/{id}) after discovery.
+	Dedupe bool
+	// DedupePatterns are extra regexes a path segment may match to count
+	// as a parameter during deduplication.
+	DedupePatterns []*regexp.Regexp
+	// TagRules attach configured tags to matching endpoints after
+	// discovery.
+	TagRules []TagRule
+	// FilterTags, when set, keeps only endpoints carrying at least one of
+	// these tags.
+	FilterTags []string
+	// OnTargetDone, when non-nil, is called with a per-target summary the
+	// moment that target's last probe finishes, before the final report
+	// is assembled.
+	OnTargetDone func(TargetSummary)
+	// SampleThreshold, when positive, caps how many endpoints one probe
+	// contributes to the result; the overflow goes to an NDJSON side file
+	// in SampleDir (default: the working directory).
+	SampleThreshold int
+	SampleDir       string
+	// Enricher, when non-nil, annotates endpoints with ASN, organization,
+	// and CDN classification after discovery.
+	Enricher *enrich.Enricher
+	// PolicyRules rewrite finding severity after every other pass, so
+	// their clauses can match verification and auth metadata.
+	PolicyRules []policy.Rule
+}
+
+// Scanner runs probes against targets.
+type Scanner struct {
+	client          *client.Client
+	probes          []probe.Probe
+	concurrency     int
+	store           *artifact.Store
+	minConfidence   float64
+	tracePath       string
+	tracer          *tracer
+	methodPolicy    MethodPolicy
+	classifyAuthz   bool
+	discoverVerbs   bool
+	versionSweep    bool
+	progress        Progress
+	now             func() time.Time
+	dedupe          bool
+	dedupePatterns  []*regexp.Regexp
+	tagRules        []TagRule
+	filterTags      []string
+	onTargetDone    func(TargetSummary)
+	sampleThreshold int
+	sampleDir       string
+	enricher        *enrich.Enricher
+	policyRules     []policy.Rule
+}
+
+// New returns a Scanner for the given options.
+func New(opts Options) *Scanner {
+	if opts.Probes == nil {
+		opts.Probes = probe.All()
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 10
+	}
+	if opts.Store != nil {
+		opts.Client.SetArtifactStore(opts.Store)
+	}
+	s := &Scanner{
+		client:          opts.Client,
+		probes:          opts.Probes,
+		concurrency:     opts.Concurrency,
+		store:           opts.Store,
+		minConfidence:   opts.MinConfidence,
+		tracePath:       opts.TracePath,
+		methodPolicy:    opts.MethodPolicy,
+		classifyAuthz:   opts.ClassifyAuth,
+		discoverVerbs:   opts.DiscoverMethods,
+		versionSweep:    opts.VersionSweep,
+		progress:        opts.Progress,
+		now:             opts.Now,
+		dedupe:          opts.Dedupe,
+		dedupePatterns:  opts.DedupePatterns,
+		tagRules:        opts.TagRules,
+		filterTags:      opts.FilterTags,
+		onTargetDone:    opts.OnTargetDone,
+		sampleThreshold: opts.SampleThreshold,
+		sampleDir:       opts.SampleDir,
+		enricher:        opts.Enricher,
+		policyRules:     opts.PolicyRules,
+	}
+	if s.sampleDir == "" {
+		s.sampleDir = "."
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	if s.methodPolicy == "" {
+		s.methodPolicy = PolicyNone
+	}
+	if opts.TracePath != "" {
+		s.tracer = &tracer{}
+	}
+	return s
+}
+
+type job struct {
+	target types.Target
+	probe  probe.Probe
+}
+
+// skipReason applies the scheduler policies: a non-empty reason means the
+// job should not run given what earlier stages discovered. Every skip is
+// logged by the caller so decisions stay auditable.
+func skipReason(discovery *probe.Discovery, j job) string {
+	address := j.target.Address()
+	if probe.CategoryOf(j.probe) == probe.CategoryHTTP && discovery.HTTPDown(address) {
+		return "fingerprinting found no HTTP service"
+	}
+	// A confirmed GraphQL endpoint makes REST path bruteforcing mostly
+	// noise; the graph is the API surface.
+	if j.probe.Name() == "learned-paths" && discovery.HasProtocol(address, types.ProtocolGraphQL) {
+		return "GraphQL endpoint confirmed; skipping path bruteforce"
+	}
+	return ""
+}
+
+// Scan runs every accepted probe against every target and returns the
+// aggregated result. Probe errors are logged, not fatal: one unreachable
+// service should not abort a scan.
+func (s *Scanner) Scan(ctx context.Context, targets []types.Target) (*types.ScanResult, error) {
+	return s.scan(ctx, targets, nil)
+}
+
+// ScanStream runs the scan like Scan but additionally delivers each
+// endpoint on events as soon as its probe reports it, before the
+// verification and auth-classification passes annotate it. The channel is
+// closed when the scan finishes; the returned result is the same final,
+// annotated form Scan produces.
+func (s *Scanner) ScanStream(ctx context.Context, targets []types.Target, events chan<- types.Endpoint) (*types.ScanResult, error) {
+	defer close(events)
+	return s.scan(ctx, targets, func(endpoint types.Endpoint) {
+		select {
+		case events <- endpoint:
+		case <-ctx.Done():
+		}
+	})
+}
+
+func (s *Scanner) scan(ctx context.Context, targets []types.Target, emit func(types.Endpoint)) (*types.ScanResult, error) {
+	scanStart := s.now()
+	result := &types.ScanResult{
+		Targets:   targets,
+		StartedAt: types.NewTime(scanStart.UTC()),
+	}
+	scanSpan := tracing.StartSpan(nil, "scan")
+	scanSpan.SetAttribute("targets", strconv.Itoa(len(targets)))
+
+	var pending []job
+	stats := map[string]*probeStats{}
+	for _, target := range targets {
+		for _, p := range s.probes {
+			if p.Accepts(target) {
+				pending = append(pending, job{target: target, probe: p})
+				st := stats[target.Address()]
+				if st == nil {
+					st = &probeStats{}
+					stats[target.Address()] = st
+				}
+				st.remaining++
+			}
+		}
+	}
+	if s.progress != nil {
+		s.progress.Start(len(pending))
+		defer s.progress.Finish()
+	}
+
+	// Jobs run in priority bands: every probe sharing a priority runs
+	// concurrently, and a band only starts once the previous one drained,
+	// so early findings reach later probes through the Discovery context.
+	bands := map[int][]job{}
+	for _, j := range pending {
+		priority := probe.PriorityOf(j.probe)
+		bands[priority] = append(bands[priority], j)
+	}
+	priorities := make([]int, 0, len(bands))
+	for priority := range bands {
+		priorities = append(priorities, priority)
+	}
+	sort.Ints(priorities)
+	discovery := &probe.Discovery{}
+	ctx = probe.WithDiscovery(ctx, discovery)
+
+	var mu sync.Mutex
+	runBand := func(band []job) error {
+		jobs := make(chan job)
+		var wg sync.WaitGroup
+		for i := 0; i < s.concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					if reason := skipReason(discovery, j); reason != "" {
+						log.Printf("scheduler: skipping probe %s against %s: %s", j.probe.Name(), j.target.Address(), reason)
+						if s.progress != nil {
+							s.progress.JobDone(j.probe.Name(), j.target.Address(), 0)
+						}
+						mu.Lock()
+						st := stats[j.target.Address()]
+						st.remaining--
+						targetDone := st.remaining == 0 && s.onTargetDone != nil
+						summary := TargetSummary{
+							Target:    j.target.Address(),
+							Probes:    st.probes,
+							Errors:    st.errors,
+							Endpoints: st.endpoints,
+							Elapsed:   s.now().Sub(scanStart),
+						}
+						mu.Unlock()
+						if targetDone {
+							s.onTargetDone(summary)
+						}
+						continue
+					}
+					started := s.now()
+					span := tracing.StartSpan(scanSpan, "probe "+j.probe.Name())
+					span.SetAttribute("probe", j.probe.Name())
+					span.SetAttribute("target", j.target.Address())
+					endpoints, err := j.probe.Run(ctx, j.target, s.client)
+					probeSeconds.Add(s.now().Sub(started).Seconds(), j.probe.Name())
+					outcome := "ok"
+					if err != nil {
+						outcome = "error"
+						span.SetError(err.Error())
+					}
+					probeRuns.Inc(j.probe.Name(), outcome)
+					span.SetAttribute("endpoints", strconv.Itoa(len(endpoints)))
+					span.End()
+					trace := ProbeTrace{
+						Probe:     j.probe.Name(),
+						Target:    j.target.Address(),
+						StartedAt: types.NewTime(started.UTC()),
+						Duration:  s.now().Sub(started).String(),
+						Endpoints: len(endpoints),
+					}
+					if s.progress != nil {
+						s.progress.JobDone(j.probe.Name(), j.target.Address(), len(endpoints))
+					}
+					var kept []types.Endpoint
+					if err != nil {
+						trace.Error = err.Error()
+						log.Printf("probe %s against %s: %v", j.probe.Name(), j.target.Address(), err)
+					} else {
+						for _, endpoint := range endpoints {
+							if endpoint.Confidence >= s.minConfidence {
+								kept = append(kept, endpoint)
+							}
+							discovery.AddProtocol(j.target.Address(), endpoint.Protocol)
+						}
+					}
+					s.tracer.record(trace)
+
+					mu.Lock()
+					st := stats[j.target.Address()]
+					st.probes++
+					st.remaining--
+					if err != nil {
+						st.errors++
+					}
+					st.endpoints += len(kept)
+					result.Endpoints = append(result.Endpoints, kept...)
+					if emit != nil {
+						for _, endpoint := range kept {
+							emit(endpoint)
+						}
+					}
+					targetDone := st.remaining == 0 && s.onTargetDone != nil
+					summary := TargetSummary{
+						Target:    j.target.Address(),
+						Probes:    st.probes,
+						Errors:    st.errors,
+						Endpoints: st.endpoints,
+						Elapsed:   s.now().Sub(scanStart),
+					}
+					mu.Unlock()
+					if targetDone {
+						s.onTargetDone(summary)
+					}
+				}
+			}()
+		}
+
+		for _, j := range band {
+			select {
+			case jobs <- j:
+			case <-ctx.Done():
+				close(jobs)
+				wg.Wait()
+				return ctx.Err()
+			}
+		}
+		close(jobs)
+		wg.Wait()
+		return nil
+	}
+	for _, priority := range priorities {
+		if err := runBand(bands[priority]); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Health = s.measureHealth(ctx, targets, stats)
+
+	annotateVersions(result.Endpoints)
+	if s.versionSweep {
+		result.Endpoints = append(result.Endpoints, s.sweepVersions(ctx, result.Endpoints)...)
+	}
+
+	sort.Slice(result.Endpoints, func(i, j int) bool {
+		if result.Endpoints[i].URL != result.Endpoints[j].URL {
+			return result.Endpoints[i].URL < result.Endpoints[j].URL
+		}
+		return result.Endpoints[i].Method < result.Endpoints[j].Method
+	})
+	if s.dedupe {
+		before := len(result.Endpoints)
+		result.Endpoints = inventory.DedupeEndpoints(result.Endpoints, s.dedupePatterns)
+		endpointsDiscovered.Add(float64(before))
+		endpointsDeduped.Add(float64(before - len(result.Endpoints)))
+	}
+	if len(s.tagRules) > 0 || len(s.filterTags) > 0 {
+		result.Endpoints = applyTags(result.Endpoints, s.tagRules, s.filterTags)
+	}
+	if s.sampleThreshold > 0 {
+		sampled, err := s.sample(result.Endpoints)
+		if err != nil {
+			return nil, err
+		}
+		result.Endpoints = sampled
+	}
+	if s.methodPolicy != PolicyNone {
+		s.verify(ctx, result.Endpoints)
+	}
+	if s.classifyAuthz {
+		s.classifyAuth(ctx, result.Endpoints)
+	}
+	if s.discoverVerbs {
+		s.discoverMethods(ctx, result.Endpoints)
+	}
+	if s.enricher != nil {
+		s.enrichEndpoints(ctx, result.Endpoints)
+	}
+	if len(s.policyRules) > 0 {
+		policy.Apply(result.Endpoints, s.policyRules)
+	}
+	result.FinishedAt = types.NewTime(s.now().UTC())
+	scanSpan.SetAttribute("endpoints", strconv.Itoa(len(result.Endpoints)))
+	scanSpan.End()
+
+	if err := s.store.WriteManifest(); err != nil {
+		return nil, err
+	}
+	if s.tracePath != "" {
+		if err := s.tracer.export(s.tracePath); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
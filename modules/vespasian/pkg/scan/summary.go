@@ -0,0 +1,40 @@
+package scan
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// TargetSummary reports one target's outcome the moment its last probe
+// finishes, long before the final report is assembled.
+type TargetSummary struct {
+	Target    string
+	Probes    int
+	Errors    int
+	Endpoints int
+	// Elapsed is the time since the scan started, not per-target work time;
+	// probes for many targets interleave.
+	Elapsed time.Duration
+}
+
+// MarkdownSummary writes one markdown bullet per finished target so a
+// multi-hour scan yields partial deliverables as it runs. Safe for
+// concurrent use.
+type MarkdownSummary struct {
+	W io.Writer
+
+	mu sync.Mutex
+}
+
+// TargetDone implements the scan.Options.OnTargetDone hook.
+func (s *MarkdownSummary) TargetDone(summary TargetSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	line := fmt.Sprintf("- `%s`: %d endpoints from %d probes", summary.Target, summary.Endpoints, summary.Probes)
+	if summary.Errors > 0 {
+		line += fmt.Sprintf(", %d failed", summary.Errors)
+	}
+	fmt.Fprintf(s.W, "%s (%s elapsed)\n", line, summary.Elapsed.Round(time.Millisecond))
+}
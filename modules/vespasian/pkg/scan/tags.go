@@ -0,0 +1,78 @@
+package scan
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TagRule attaches tags to every endpoint whose URL matches the pattern.
+// Rules come from configuration and encode org-specific triage: tagging
+// payment routes for priority review, labeling internal hosts, and so on.
+type TagRule struct {
+	// Pattern is matched against the full endpoint URL, so it can anchor
+	// on host, path, or both.
+	Pattern *regexp.Regexp
+	// Tags are attached to matching endpoints.
+	Tags []string
+}
+
+// applyTags runs the tag rules over the endpoints and, when a tag filter
+// is set, returns only the endpoints carrying at least one wanted tag.
+func applyTags(endpoints []types.Endpoint, rules []TagRule, filter []string) []types.Endpoint {
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		var tags []string
+		for _, rule := range rules {
+			if rule.Pattern.MatchString(endpoint.URL) {
+				tags = append(tags, rule.Tags...)
+			}
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		if endpoint.Metadata == nil {
+			endpoint.Metadata = map[string]string{}
+		}
+		endpoint.Metadata["tags"] = strings.Join(dedupeTags(tags), ", ")
+	}
+
+	if len(filter) == 0 {
+		return endpoints
+	}
+	kept := endpoints[:0]
+	for _, endpoint := range endpoints {
+		if hasAnyTag(endpoint, filter) {
+			kept = append(kept, endpoint)
+		}
+	}
+	return kept
+}
+
+func dedupeTags(tags []string) []string {
+	seen := map[string]bool{}
+	out := tags[:0]
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func hasAnyTag(endpoint types.Endpoint, wanted []string) bool {
+	tagged := endpoint.Metadata["tags"]
+	if tagged == "" {
+		return false
+	}
+	for _, tag := range strings.Split(tagged, ", ") {
+		for _, want := range wanted {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
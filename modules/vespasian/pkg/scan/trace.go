@@ -0,0 +1,56 @@
+package scan
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// ProbeTrace records one probe execution against one target, for
+// after-the-fact debugging of slow or empty scans.
+type ProbeTrace struct {
+	Probe     string     `json:"probe"`
+	Target    string     `json:"target"`
+	StartedAt types.Time `json:"started_at"`
+	Duration  string     `json:"duration"`
+	Endpoints int        `json:"endpoints"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// tracer accumulates probe traces during a scan.
+type tracer struct {
+	mu     sync.Mutex
+	traces []ProbeTrace
+}
+
+// record appends a trace entry. A nil tracer discards it.
+func (t *tracer) record(trace ProbeTrace) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.traces = append(t.traces, trace)
+	t.mu.Unlock()
+}
+
+// export writes the collected traces as JSON to path, ordered by start
+// time so the file reads as a timeline.
+func (t *tracer) export(path string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	traces := make([]ProbeTrace, len(t.traces))
+	copy(traces, t.traces)
+	t.mu.Unlock()
+
+	sort.Slice(traces, func(i, j int) bool { return traces[i].StartedAt.Before(traces[j].StartedAt.Time) })
+	data, err := json.MarshalIndent(traces, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
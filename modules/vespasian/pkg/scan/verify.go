@@ -0,0 +1,106 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// MethodPolicy controls which HTTP methods endpoint verification is
+// allowed to send. Discovered endpoints can carry unsafe methods (DELETE
+// on /api/orders/{id}), and sending those against production systems is an
+// operator decision, not a default.
+type MethodPolicy string
+
+const (
+	// PolicyNone disables verification requests entirely.
+	PolicyNone MethodPolicy = "none"
+	// PolicySafe verifies with the discovered method only when it is
+	// safe (GET, HEAD, OPTIONS); other endpoints are left unverified.
+	PolicySafe MethodPolicy = "safe"
+	// PolicyAll verifies every endpoint with its discovered method.
+	PolicyAll MethodPolicy = "all"
+)
+
+// ParseMethodPolicy validates a policy string from configuration.
+func ParseMethodPolicy(s string) (MethodPolicy, error) {
+	switch MethodPolicy(s) {
+	case PolicyNone, PolicySafe, PolicyAll:
+		return MethodPolicy(s), nil
+	case "":
+		return PolicyNone, nil
+	}
+	return "", fmt.Errorf("scan: unknown method policy %q", s)
+}
+
+// safeMethods never modify server state per RFC 9110.
+var safeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+}
+
+// allows reports whether the policy permits sending method.
+func (p MethodPolicy) allows(method string) bool {
+	switch p {
+	case PolicyAll:
+		return true
+	case PolicySafe:
+		return safeMethods[method]
+	}
+	return false
+}
+
+// verify issues a request to each endpoint the policy permits and records
+// the observed status, content type, and body size in metadata. HTTP-family
+// endpoints only; protocol probes already confirmed the others by speaking
+// their protocol.
+func (s *Scanner) verify(ctx context.Context, endpoints []types.Endpoint) {
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if endpoint.Protocol != types.ProtocolHTTP {
+			continue
+		}
+		method := endpoint.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		if !s.methodPolicy.allows(method) {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint.URL, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := s.client.Do(req)
+		if endpoint.Metadata == nil {
+			endpoint.Metadata = map[string]string{}
+		}
+		if err != nil {
+			endpoint.Metadata["verified"] = "error"
+			continue
+		}
+		endpoint.Metadata["verified"] = "true"
+		endpoint.Metadata["verified_status"] = strconv.Itoa(resp.StatusCode)
+		if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+			endpoint.Metadata["content_type"] = contentType
+		}
+		endpoint.Metadata["content_length"] = strconv.Itoa(len(resp.Body))
+		// A 401/403 on the verification request is auth evidence worth
+		// keeping even when the dedicated auth classification pass is
+		// off; that pass overwrites these keys when it runs.
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			endpoint.Metadata["auth"] = "required"
+		case http.StatusForbidden:
+			endpoint.Metadata["auth"] = "forbidden"
+		}
+		if resp.Proto != "" {
+			endpoint.Metadata["http_version"] = resp.Proto
+		}
+		recordDeprecation(endpoint, resp.Header)
+	}
+}
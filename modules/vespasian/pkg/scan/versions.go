@@ -0,0 +1,203 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// API version handling: most services version in the path (/v1/, /v2/,
+// /api/2023-01/) or a query parameter (?api-version=2). The annotation
+// pass records which scheme and version each endpoint uses; the sweep
+// pass then requests the numerically adjacent versions of every
+// versioned endpoint, because the /v1/ a team forgot to turn off is
+// routinely the one missing the auth fix /v2/ shipped with.
+
+// versionSegmentRe matches one path segment that is a version marker:
+// v-prefixed numerics (v1, v2.1) or date versions (2023-01, 2023-01-15).
+var versionSegmentRe = regexp.MustCompile(`^(?i)(v\d+(?:\.\d+)?|\d{4}-\d{2}(?:-\d{2})?)$`)
+
+// versionParams are query parameter names APIs use for versioning.
+var versionParams = []string{"api-version", "api_version", "version", "v"}
+
+// annotateVersions records the versioning scheme and version on every
+// endpoint that carries one.
+func annotateVersions(endpoints []types.Endpoint) {
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if endpoint.Protocol != types.ProtocolHTTP {
+			continue
+		}
+		version, scheme := endpointVersion(endpoint.URL)
+		if version == "" {
+			continue
+		}
+		if endpoint.Metadata == nil {
+			endpoint.Metadata = map[string]string{}
+		}
+		endpoint.Metadata["api_version"] = version
+		endpoint.Metadata["versioning"] = scheme
+	}
+}
+
+// endpointVersion extracts the version marker from a URL: the first
+// versioned path segment, else a recognized version query parameter.
+func endpointVersion(raw string) (version, scheme string) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", ""
+	}
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if versionSegmentRe.MatchString(segment) {
+			return segment, "path"
+		}
+	}
+	query := parsed.Query()
+	for _, param := range versionParams {
+		if value := query.Get(param); value != "" {
+			return value, "query"
+		}
+	}
+	return "", ""
+}
+
+// sweepVersions probes the adjacent versions of every versioned HTTP
+// endpoint and returns the ones that answered. Only numeric v-versions
+// sweep — date versions cannot be guessed usefully. A live version below
+// the highest one observed for the same path is flagged as a
+// deprecated-but-live finding.
+func (s *Scanner) sweepVersions(ctx context.Context, endpoints []types.Endpoint) []types.Endpoint {
+	known := map[string]bool{}
+	// Highest version number seen per candidate URL's version-erased
+	// form, so the sweep can tell "older than anything discovered" from
+	// "newer".
+	maxSeen := map[string]int{}
+	type candidate struct {
+		url     string
+		version string
+		number  int
+		origin  string
+		group   string
+	}
+	var candidates []candidate
+
+	for _, endpoint := range endpoints {
+		if endpoint.Protocol != types.ProtocolHTTP {
+			continue
+		}
+		known[endpoint.URL] = true
+		rewrite, version := versionRewriter(endpoint.URL)
+		number, ok := versionNumber(version)
+		if !ok {
+			continue
+		}
+		group := rewrite("{version}")
+		if number > maxSeen[group] {
+			maxSeen[group] = number
+		}
+		for _, adjacent := range []int{number - 1, number + 1} {
+			if adjacent < 1 {
+				continue
+			}
+			next := "v" + strconv.Itoa(adjacent)
+			candidates = append(candidates, candidate{
+				url:     rewrite(next),
+				version: next,
+				number:  adjacent,
+				origin:  endpoint.URL,
+				group:   group,
+			})
+		}
+	}
+
+	var found []types.Endpoint
+	probed := map[string]bool{}
+	for _, c := range candidates {
+		if known[c.url] || probed[c.url] {
+			continue
+		}
+		probed[c.url] = true
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+		if err != nil {
+			continue
+		}
+		resp, err := s.client.Do(req)
+		if err != nil || resp.StatusCode >= 400 {
+			continue
+		}
+		metadata := map[string]string{
+			"api_version": c.version,
+			"versioning":  "path",
+			"origin":      c.origin,
+			"status":      strconv.Itoa(resp.StatusCode),
+		}
+		if c.number < maxSeen[c.group] {
+			metadata["risk"] = "deprecated-api-version"
+			metadata["deprecated"] = "true"
+		}
+		found = append(found, types.Endpoint{
+			URL:        c.url,
+			Method:     "GET",
+			Protocol:   types.ProtocolHTTP,
+			Source:     "version-sweep",
+			Confidence: types.ConfidenceMedium,
+			Metadata:   metadata,
+		})
+	}
+	return found
+}
+
+// versionRewriter returns the URL's version marker and a function that
+// rebuilds the URL with the marker replaced — only in the path segment
+// or query parameter that carried it, never in the host.
+func versionRewriter(raw string) (rewrite func(string) string, version string) {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, ""
+	}
+	segments := strings.Split(parsed.Path, "/")
+	for i, segment := range segments {
+		if versionSegmentRe.MatchString(segment) {
+			index := i
+			return func(replacement string) string {
+				rebuilt := *parsed
+				swapped := append([]string(nil), segments...)
+				swapped[index] = replacement
+				rebuilt.Path = strings.Join(swapped, "/")
+				return rebuilt.String()
+			}, segment
+		}
+	}
+	query := parsed.Query()
+	for _, param := range versionParams {
+		if value := query.Get(param); value != "" {
+			name := param
+			return func(replacement string) string {
+				rebuilt := *parsed
+				swapped := rebuilt.Query()
+				swapped.Set(name, replacement)
+				rebuilt.RawQuery = swapped.Encode()
+				return rebuilt.String()
+			}, value
+		}
+	}
+	return nil, ""
+}
+
+// versionNumber parses a v-prefixed integer version; v2.1 and date
+// versions report false.
+func versionNumber(version string) (int, bool) {
+	if len(version) < 2 || (version[0] != 'v' && version[0] != 'V') {
+		return 0, false
+	}
+	n, err := strconv.Atoi(version[1:])
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,57 @@
+// Package secrets detects API keys and credentials leaked in crawled
+// content. Matches are redacted before they are recorded: the point is to
+// flag the leak for the operator, not to copy the credential into scan
+// output.
+package secrets
+
+import "regexp"
+
+// Rule is one secret pattern.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Match is a detected secret with its value redacted.
+type Match struct {
+	Rule     string
+	Redacted string
+}
+
+// rules cover the credential formats most often left in front-end bundles.
+var rules = []Rule{
+	{"aws-access-key-id", regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36,}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[baprs]-[0-9A-Za-z\-]{10,}\b`)},
+	{"stripe-key", regexp.MustCompile(`\b[sr]k_live_[0-9a-zA-Z]{24,}\b`)},
+	{"twilio-key", regexp.MustCompile(`\bSK[0-9a-fA-F]{32}\b`)},
+	{"sendgrid-key", regexp.MustCompile(`\bSG\.[0-9A-Za-z_\-]{22}\.[0-9A-Za-z_\-]{43}\b`)},
+	{"jwt", regexp.MustCompile(`\beyJ[0-9A-Za-z_\-]{10,}\.[0-9A-Za-z_\-]{10,}\.[0-9A-Za-z_\-]{10,}\b`)},
+	{"private-key-block", regexp.MustCompile(`-----BEGIN (?:RSA |EC |OPENSSH |DSA |PGP )?PRIVATE KEY`)},
+	{"generic-api-key", regexp.MustCompile(`(?i)["']?(?:api[_-]?key|api[_-]?secret|access[_-]?token)["']?\s*[:=]\s*["'][0-9A-Za-z_\-]{16,}["']`)},
+}
+
+// Scan returns redacted matches for every rule that fires on body. Each
+// rule reports at most once per body; the count of distinct leaks matters
+// more than every occurrence.
+func Scan(body []byte) []Match {
+	var matches []Match
+	for _, rule := range rules {
+		m := rule.Pattern.Find(body)
+		if m == nil {
+			continue
+		}
+		matches = append(matches, Match{Rule: rule.Name, Redacted: redact(string(m))})
+	}
+	return matches
+}
+
+// redact keeps enough of the match to locate it in the source without
+// reproducing the credential.
+func redact(s string) string {
+	if len(s) <= 8 {
+		return s[:2] + "..."
+	}
+	return s[:6] + "..." + s[len(s)-2:]
+}
@@ -0,0 +1,632 @@
+package store
+
+// This file reads and writes the store as a SQLite database file.
+// Vespasian vendors no dependencies and the standard library ships no
+// SQLite driver, so the file format is implemented directly: table
+// b-trees of leaf and interior pages, record serial types, and overflow
+// chains, per https://sqlite.org/fileformat2.html. The subset written
+// here — two rowid tables, no indexes, no freelist, no WAL — is small,
+// but the files are genuine SQLite databases any sqlite3 binary can
+// open, which is the point of keeping a stable schema.
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+const (
+	sqliteMagic = "SQLite format 3\x00"
+	pageSize    = 4096
+	// maxLocal and minLocal bound how much of a record stays on the
+	// b-tree page before spilling to overflow pages (the standard
+	// formulas for a table leaf with no reserved bytes).
+	maxLocal = pageSize - 35
+	minLocal = (pageSize-12)*32/255 - 23
+
+	leafPage     = 0x0d
+	interiorPage = 0x05
+)
+
+// The schema the master table declares. Column order is load-bearing:
+// the reader decodes records by position.
+const (
+	sqlScans    = "CREATE TABLE scans (id INTEGER PRIMARY KEY, started_at TEXT, targets TEXT, findings INTEGER)"
+	sqlFindings = "CREATE TABLE findings (id INTEGER PRIMARY KEY, scan_id INTEGER, url TEXT, method TEXT, protocol TEXT, source TEXT, confidence REAL, metadata TEXT)"
+)
+
+// writeSQLite serializes the store and writes it to path.
+func writeSQLite(path string, scans []Scan, findings []Finding) error {
+	w := &dbWriter{}
+	w.alloc() // page 1: file header plus the sqlite_master root
+
+	scanCells := make([]tableCell, 0, len(scans))
+	for _, scan := range scans {
+		targets, err := json.Marshal(scan.Targets)
+		if err != nil {
+			return err
+		}
+		// id INTEGER PRIMARY KEY aliases the rowid; the column itself
+		// stores NULL.
+		scanCells = append(scanCells, tableCell{
+			rowid: int64(scan.ID),
+			payload: encodeRecord(nil, scan.StartedAt.UTC().Format(time.RFC3339Nano),
+				string(targets), int64(scan.Findings)),
+		})
+	}
+	scansRoot := w.buildTable(scanCells)
+
+	findingCells := make([]tableCell, 0, len(findings))
+	for i, finding := range findings {
+		var metadata any
+		if finding.Metadata != nil {
+			encoded, err := json.Marshal(finding.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(encoded)
+		}
+		findingCells = append(findingCells, tableCell{
+			rowid: int64(i + 1),
+			payload: encodeRecord(nil, int64(finding.ScanID), finding.URL, finding.Method,
+				string(finding.Protocol), finding.Source, finding.Confidence, metadata),
+		})
+	}
+	findingsRoot := w.buildTable(findingCells)
+
+	master := []tableCell{
+		{rowid: 1, payload: encodeRecord("table", "scans", "scans", int64(scansRoot), sqlScans)},
+		{rowid: 2, payload: encodeRecord("table", "findings", "findings", int64(findingsRoot), sqlFindings)},
+	}
+	if err := w.writeMaster(master); err != nil {
+		return err
+	}
+	w.writeHeader()
+
+	data := make([]byte, 0, len(w.pages)*pageSize)
+	for _, page := range w.pages {
+		data = append(data, page...)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// dbWriter accumulates database pages; pages[i] is page number i+1.
+type dbWriter struct {
+	pages [][]byte
+}
+
+func (w *dbWriter) alloc() int {
+	w.pages = append(w.pages, make([]byte, pageSize))
+	return len(w.pages)
+}
+
+// tableCell is one row awaiting packing: its rowid key and the encoded
+// record payload.
+type tableCell struct {
+	rowid   int64
+	payload []byte
+}
+
+// buildTable packs rows into leaf pages, stacks interior pages above
+// them as needed, and returns the root page number.
+func (w *dbWriter) buildTable(cells []tableCell) int {
+	encoded := make([][]byte, len(cells))
+	for i, cell := range cells {
+		encoded[i] = w.encodeCell(cell)
+	}
+
+	var pages []int
+	var keys []int64 // largest rowid in each page's subtree
+	i := 0
+	for {
+		n := w.alloc()
+		page := w.pages[n-1]
+		count, top := 0, pageSize
+		for i < len(encoded) {
+			cell := encoded[i]
+			if top-len(cell) < 8+2*(count+1) {
+				break
+			}
+			top -= len(cell)
+			copy(page[top:], cell)
+			binary.BigEndian.PutUint16(page[8+2*count:], uint16(top))
+			count++
+			i++
+		}
+		page[0] = leafPage
+		binary.BigEndian.PutUint16(page[3:], uint16(count))
+		binary.BigEndian.PutUint16(page[5:], uint16(top))
+		pages = append(pages, n)
+		var key int64
+		if count > 0 {
+			key = cells[i-1].rowid
+		}
+		keys = append(keys, key)
+		if i >= len(encoded) {
+			break
+		}
+	}
+	for len(pages) > 1 {
+		pages, keys = w.buildInteriorLevel(pages, keys)
+	}
+	return pages[0]
+}
+
+// buildInteriorLevel packs one level of interior pages over children and
+// returns the new level.
+func (w *dbWriter) buildInteriorLevel(children []int, keys []int64) ([]int, []int64) {
+	var pages []int
+	var outKeys []int64
+	i := 0
+	for i < len(children) {
+		n := w.alloc()
+		page := w.pages[n-1]
+		count, top := 0, pageSize
+		for i < len(children)-1 {
+			cell := binary.BigEndian.AppendUint32(nil, uint32(children[i]))
+			cell = appendVarint(cell, uint64(keys[i]))
+			if top-len(cell) < 12+2*(count+1) {
+				break
+			}
+			top -= len(cell)
+			copy(page[top:], cell)
+			binary.BigEndian.PutUint16(page[12+2*count:], uint16(top))
+			count++
+			i++
+		}
+		// The next child becomes this page's right-most pointer.
+		page[0] = interiorPage
+		binary.BigEndian.PutUint16(page[3:], uint16(count))
+		binary.BigEndian.PutUint16(page[5:], uint16(top))
+		binary.BigEndian.PutUint32(page[8:], uint32(children[i]))
+		pages = append(pages, n)
+		outKeys = append(outKeys, keys[i])
+		i++
+	}
+	return pages, outKeys
+}
+
+// encodeCell renders a leaf cell, spilling payload beyond the local
+// threshold onto a freshly allocated overflow chain.
+func (w *dbWriter) encodeCell(cell tableCell) []byte {
+	p := len(cell.payload)
+	out := appendVarint(nil, uint64(p))
+	out = appendVarint(out, uint64(cell.rowid))
+	if p <= maxLocal {
+		return append(out, cell.payload...)
+	}
+	local := minLocal + (p-minLocal)%(pageSize-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	out = append(out, cell.payload[:local]...)
+	rest := cell.payload[local:]
+	first := w.alloc()
+	for n := first; ; {
+		page := w.pages[n-1]
+		take := min(len(rest), pageSize-4)
+		copy(page[4:], rest[:take])
+		rest = rest[take:]
+		if len(rest) == 0 {
+			break
+		}
+		next := w.alloc()
+		binary.BigEndian.PutUint32(page[0:], uint32(next))
+		n = next
+	}
+	return binary.BigEndian.AppendUint32(out, uint32(first))
+}
+
+// writeMaster packs the sqlite_master rows into page 1 after the file
+// header. Two short rows always fit; a failure here means the schema
+// constants grew past a page, which is a programming error worth
+// surfacing.
+func (w *dbWriter) writeMaster(cells []tableCell) error {
+	page := w.pages[0]
+	count, top := 0, pageSize
+	for _, cell := range cells {
+		encoded := w.encodeCell(cell)
+		if top-len(encoded) < 100+8+2*(count+1) {
+			return fmt.Errorf("store: schema does not fit the master page")
+		}
+		top -= len(encoded)
+		copy(page[top:], encoded)
+		binary.BigEndian.PutUint16(page[100+8+2*count:], uint16(top))
+		count++
+	}
+	page[100] = leafPage
+	binary.BigEndian.PutUint16(page[103:], uint16(count))
+	binary.BigEndian.PutUint16(page[105:], uint16(top))
+	return nil
+}
+
+// writeHeader fills in the 100-byte file header on page 1.
+func (w *dbWriter) writeHeader() {
+	h := w.pages[0]
+	copy(h, sqliteMagic)
+	binary.BigEndian.PutUint16(h[16:], pageSize)
+	h[18], h[19] = 1, 1 // legacy (rollback-journal) file format
+	h[21], h[22], h[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(h[24:], 1) // file change counter
+	binary.BigEndian.PutUint32(h[28:], uint32(len(w.pages)))
+	binary.BigEndian.PutUint32(h[40:], 1) // schema cookie
+	binary.BigEndian.PutUint32(h[44:], 4) // schema format number
+	binary.BigEndian.PutUint32(h[56:], 1) // UTF-8
+	binary.BigEndian.PutUint32(h[60:], schemaVersion)
+	binary.BigEndian.PutUint32(h[92:], 1)       // version-valid-for
+	binary.BigEndian.PutUint32(h[96:], 3036000) // library version stamp
+}
+
+// encodeRecord serializes column values in the record format. Supported
+// value types mirror what the schema stores: nil, int64, float64, and
+// string.
+func encodeRecord(values ...any) []byte {
+	var header, body []byte
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			header = appendVarint(header, 0)
+		case int64:
+			serial, size := intSerial(v)
+			header = appendVarint(header, serial)
+			for i := size - 1; i >= 0; i-- {
+				body = append(body, byte(v>>(8*i)))
+			}
+		case float64:
+			header = appendVarint(header, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(v))
+		case string:
+			header = appendVarint(header, uint64(2*len(v)+13))
+			body = append(body, v...)
+		default:
+			panic(fmt.Sprintf("store: unsupported record type %T", value))
+		}
+	}
+	// The header length varint counts itself.
+	size := 1
+	for varintLen(uint64(len(header)+size)) != size {
+		size = varintLen(uint64(len(header) + size))
+	}
+	record := appendVarint(nil, uint64(len(header)+size))
+	record = append(record, header...)
+	return append(record, body...)
+}
+
+// intSerial picks the smallest integer serial type holding v and returns
+// it with the body size in bytes.
+func intSerial(v int64) (uint64, int) {
+	switch {
+	case v == 0:
+		return 8, 0
+	case v == 1:
+		return 9, 0
+	case v >= -1<<7 && v < 1<<7:
+		return 1, 1
+	case v >= -1<<15 && v < 1<<15:
+		return 2, 2
+	case v >= -1<<23 && v < 1<<23:
+		return 3, 3
+	case v >= -1<<31 && v < 1<<31:
+		return 4, 4
+	case v >= -1<<47 && v < 1<<47:
+		return 5, 6
+	default:
+		return 6, 8
+	}
+}
+
+// readSQLite parses a database file written by writeSQLite (or any
+// SQLite build honoring the same schema).
+func readSQLite(data []byte) ([]Scan, []Finding, error) {
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil, nil, fmt.Errorf("not a SQLite database")
+	}
+	size := int(binary.BigEndian.Uint16(data[16:]))
+	if size == 1 {
+		size = 65536
+	}
+	if version := binary.BigEndian.Uint32(data[60:]); version > schemaVersion {
+		return nil, nil, fmt.Errorf("schema version %d is newer than this build understands", version)
+	}
+	r := &dbReader{data: data, pageSize: size, reserved: int(data[20])}
+
+	roots := map[string]int64{}
+	err := r.walk(1, func(_ int64, record []any) {
+		if len(record) >= 4 && asString(record[0]) == "table" {
+			roots[asString(record[1])] = asInt(record[3])
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var scans []Scan
+	if root := roots["scans"]; root != 0 {
+		err = r.walk(int(root), func(rowid int64, record []any) {
+			if len(record) < 4 {
+				return
+			}
+			scan := Scan{ID: int(rowid), Findings: int(asInt(record[3]))}
+			if parsed, err := time.Parse(time.RFC3339Nano, asString(record[1])); err == nil {
+				scan.StartedAt = types.NewTime(parsed)
+			}
+			json.Unmarshal([]byte(asString(record[2])), &scan.Targets)
+			scans = append(scans, scan)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var findings []Finding
+	if root := roots["findings"]; root != 0 {
+		err = r.walk(int(root), func(_ int64, record []any) {
+			if len(record) < 8 {
+				return
+			}
+			finding := Finding{
+				ScanID:     int(asInt(record[1])),
+				URL:        asString(record[2]),
+				Method:     asString(record[3]),
+				Protocol:   asString(record[4]),
+				Source:     asString(record[5]),
+				Confidence: asFloat(record[6]),
+			}
+			if record[7] != nil {
+				json.Unmarshal([]byte(asString(record[7])), &finding.Metadata)
+			}
+			findings = append(findings, finding)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return scans, findings, nil
+}
+
+// dbReader walks table b-trees in a database image.
+type dbReader struct {
+	data     []byte
+	pageSize int
+	reserved int
+}
+
+func (r *dbReader) page(n int) ([]byte, error) {
+	start := (n - 1) * r.pageSize
+	if n < 1 || start+r.pageSize > len(r.data) {
+		return nil, fmt.Errorf("page %d out of range", n)
+	}
+	return r.data[start : start+r.pageSize], nil
+}
+
+// walk visits every row of the table rooted at pageNum in rowid order.
+func (r *dbReader) walk(pageNum int, visit func(rowid int64, record []any)) error {
+	page, err := r.page(pageNum)
+	if err != nil {
+		return err
+	}
+	header := 0
+	if pageNum == 1 {
+		header = 100
+	}
+	count := int(binary.BigEndian.Uint16(page[header+3:]))
+	switch page[header] {
+	case interiorPage:
+		for i := 0; i < count; i++ {
+			ptr := int(binary.BigEndian.Uint16(page[header+12+2*i:]))
+			if ptr+4 > len(page) {
+				return fmt.Errorf("malformed interior page %d", pageNum)
+			}
+			child := int(binary.BigEndian.Uint32(page[ptr:]))
+			if err := r.walk(child, visit); err != nil {
+				return err
+			}
+		}
+		right := int(binary.BigEndian.Uint32(page[header+8:]))
+		return r.walk(right, visit)
+	case leafPage:
+		for i := 0; i < count; i++ {
+			ptr := int(binary.BigEndian.Uint16(page[header+8+2*i:]))
+			rowid, payload, err := r.leafCell(page, ptr)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", pageNum, err)
+			}
+			record, err := decodeRecord(payload)
+			if err != nil {
+				return fmt.Errorf("page %d: %w", pageNum, err)
+			}
+			visit(rowid, record)
+		}
+		return nil
+	default:
+		return fmt.Errorf("page %d has unexpected type %#x", pageNum, page[header])
+	}
+}
+
+// leafCell decodes the cell at ptr, following overflow chains for
+// payloads that spilled off the page.
+func (r *dbReader) leafCell(page []byte, ptr int) (int64, []byte, error) {
+	if ptr >= len(page) {
+		return 0, nil, fmt.Errorf("cell pointer out of range")
+	}
+	total, n := readVarint(page[ptr:])
+	ptr += n
+	rowid, n := readVarint(page[ptr:])
+	ptr += n
+	usable := r.pageSize - r.reserved
+	length := int(total)
+	local := length
+	if length > usable-35 {
+		local = (usable-12)*32/255 - 23 + (length-((usable-12)*32/255-23))%(usable-4)
+		if local > usable-35 {
+			local = (usable-12)*32/255 - 23
+		}
+	}
+	if ptr+local > len(page) {
+		return 0, nil, fmt.Errorf("cell payload out of range")
+	}
+	if local == length {
+		return int64(rowid), page[ptr : ptr+local], nil
+	}
+	payload := make([]byte, 0, length)
+	payload = append(payload, page[ptr:ptr+local]...)
+	next := int(binary.BigEndian.Uint32(page[ptr+local:]))
+	for next != 0 {
+		overflow, err := r.page(next)
+		if err != nil {
+			return 0, nil, err
+		}
+		take := min(length-len(payload), usable-4)
+		payload = append(payload, overflow[4:4+take]...)
+		next = int(binary.BigEndian.Uint32(overflow[0:]))
+	}
+	if len(payload) != length {
+		return 0, nil, fmt.Errorf("overflow chain ends short of payload")
+	}
+	return int64(rowid), payload, nil
+}
+
+// decodeRecord parses a record into column values: nil, int64, float64,
+// string, or []byte.
+func decodeRecord(payload []byte) ([]any, error) {
+	headerLen, n := readVarint(payload)
+	if int(headerLen) > len(payload) {
+		return nil, fmt.Errorf("record header overruns payload")
+	}
+	header := payload[n:headerLen]
+	body := payload[headerLen:]
+	var values []any
+	for len(header) > 0 {
+		serial, n := readVarint(header)
+		header = header[n:]
+		value, size, err := decodeValue(serial, body)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		body = body[size:]
+	}
+	return values, nil
+}
+
+func decodeValue(serial uint64, body []byte) (any, int, error) {
+	intSizes := [...]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 6, 6: 8}
+	switch {
+	case serial == 0:
+		return nil, 0, nil
+	case serial >= 1 && serial <= 6:
+		size := intSizes[serial]
+		if len(body) < size {
+			return nil, 0, fmt.Errorf("record body truncated")
+		}
+		var v uint64
+		for _, b := range body[:size] {
+			v = v<<8 | uint64(b)
+		}
+		// Sign-extend from the serialized width.
+		shift := 64 - 8*size
+		return int64(v<<shift) >> shift, size, nil
+	case serial == 7:
+		if len(body) < 8 {
+			return nil, 0, fmt.Errorf("record body truncated")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(body)), 8, nil
+	case serial == 8:
+		return int64(0), 0, nil
+	case serial == 9:
+		return int64(1), 0, nil
+	case serial >= 12 && serial%2 == 0:
+		size := int(serial-12) / 2
+		if len(body) < size {
+			return nil, 0, fmt.Errorf("record body truncated")
+		}
+		return body[:size], size, nil
+	case serial >= 13:
+		size := int(serial-13) / 2
+		if len(body) < size {
+			return nil, 0, fmt.Errorf("record body truncated")
+		}
+		return string(body[:size]), size, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported serial type %d", serial)
+	}
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func asInt(v any) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func asFloat(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// appendVarint appends v in SQLite's big-endian base-128 varint
+// encoding.
+func appendVarint(b []byte, v uint64) []byte {
+	if v>>56 != 0 {
+		b = append(b, byte(v>>57|0x80), byte(v>>50|0x80), byte(v>>43|0x80),
+			byte(v>>36|0x80), byte(v>>29|0x80), byte(v>>22|0x80),
+			byte(v>>15|0x80), byte(v>>8|0x80), byte(v))
+		return b
+	}
+	var groups []byte
+	for {
+		groups = append(groups, byte(v&0x7f))
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	for i := len(groups) - 1; i > 0; i-- {
+		b = append(b, groups[i]|0x80)
+	}
+	return append(b, groups[0])
+}
+
+func varintLen(v uint64) int {
+	if v>>56 != 0 {
+		return 9
+	}
+	n := 1
+	for v >>= 7; v != 0; v >>= 7 {
+		n++
+	}
+	return n
+}
+
+// readVarint decodes a varint and reports how many bytes it spanned.
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 9; i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, len(b)
+}
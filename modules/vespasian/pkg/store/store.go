@@ -0,0 +1,191 @@
+// Package store persists scans, their targets, and their findings across
+// runs, so repeated scans of the same estate can be queried and compared
+// over time. The backing file is a SQLite database — a scans table and a
+// findings table joined by scan ID — written and read directly (see
+// sqlite.go), so the store stays dependency-free while remaining
+// queryable with any stock sqlite3 binary. Stores written by earlier
+// builds in the interim JSON layout are still read and are rewritten as
+// SQLite on the next save.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// schemaVersion guards the on-disk layout (stored in the SQLite
+// user_version header field); a reader refuses files written by a newer
+// schema rather than misinterpreting them.
+const schemaVersion = 1
+
+// Scan is one recorded scan run.
+type Scan struct {
+	ID        int        `json:"id"`
+	StartedAt types.Time `json:"started_at"`
+	Targets   []string   `json:"targets"`
+	Findings  int        `json:"findings"`
+}
+
+// Finding is one endpoint as observed by one scan.
+type Finding struct {
+	ScanID     int               `json:"scan_id"`
+	URL        string            `json:"url"`
+	Method     string            `json:"method"`
+	Protocol   string            `json:"protocol"`
+	Source     string            `json:"source"`
+	Confidence float64           `json:"confidence"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Store is the scan database.
+type Store struct {
+	path     string
+	Scans    []Scan
+	Findings []Finding
+}
+
+// dbFile is the interim JSON layout earlier builds wrote; Open still
+// reads it so existing stores migrate transparently.
+type dbFile struct {
+	SchemaVersion int       `json:"schema_version"`
+	Scans         []Scan    `json:"scans"`
+	Findings      []Finding `json:"findings"`
+}
+
+// Open loads the database at path, starting empty if the file does not
+// exist yet.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) >= len(sqliteMagic) && string(data[:len(sqliteMagic)]) == sqliteMagic {
+		scans, findings, err := readSQLite(data)
+		if err != nil {
+			return nil, fmt.Errorf("store %s: %w", path, err)
+		}
+		s.Scans = scans
+		s.Findings = findings
+		return s, nil
+	}
+	var file dbFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("store %s: %w", path, err)
+	}
+	if file.SchemaVersion > schemaVersion {
+		return nil, fmt.Errorf("store %s: schema version %d is newer than this build understands", path, file.SchemaVersion)
+	}
+	s.Scans = file.Scans
+	s.Findings = file.Findings
+	return s, nil
+}
+
+// AppendScan records a finished scan with its targets and findings and
+// returns the new scan's ID.
+func (s *Store) AppendScan(result *types.ScanResult) int {
+	id := 1
+	if n := len(s.Scans); n > 0 {
+		id = s.Scans[n-1].ID + 1
+	}
+	targets := make([]string, 0, len(result.Targets))
+	for _, target := range result.Targets {
+		targets = append(targets, target.Address())
+	}
+	s.Scans = append(s.Scans, Scan{
+		ID:        id,
+		StartedAt: result.StartedAt,
+		Targets:   targets,
+		Findings:  len(result.Endpoints),
+	})
+	for _, endpoint := range result.Endpoints {
+		s.Findings = append(s.Findings, Finding{
+			ScanID:     id,
+			URL:        endpoint.URL,
+			Method:     endpoint.Method,
+			Protocol:   string(endpoint.Protocol),
+			Source:     endpoint.Source,
+			Confidence: endpoint.Confidence,
+			Metadata:   endpoint.Metadata,
+		})
+	}
+	return id
+}
+
+// Save writes the database back to its file.
+func (s *Store) Save() error {
+	return writeSQLite(s.path, s.Scans, s.Findings)
+}
+
+// Filter selects findings in a Query. Zero fields match everything.
+type Filter struct {
+	// ScanID restricts to one scan; zero means all scans.
+	ScanID int
+	// Source restricts to one probe's findings.
+	Source string
+	// URL is a substring the finding URL must contain.
+	URL string
+}
+
+// Query returns the findings matching the filter, ordered by scan then
+// URL then method.
+func (s *Store) Query(filter Filter) []Finding {
+	var matches []Finding
+	for _, finding := range s.Findings {
+		if filter.ScanID != 0 && finding.ScanID != filter.ScanID {
+			continue
+		}
+		if filter.Source != "" && finding.Source != filter.Source {
+			continue
+		}
+		if filter.URL != "" && !strings.Contains(finding.URL, filter.URL) {
+			continue
+		}
+		matches = append(matches, finding)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].ScanID != matches[j].ScanID {
+			return matches[i].ScanID < matches[j].ScanID
+		}
+		if matches[i].URL != matches[j].URL {
+			return matches[i].URL < matches[j].URL
+		}
+		return matches[i].Method < matches[j].Method
+	})
+	return matches
+}
+
+// Diff reports the findings new in scan b relative to scan a and the
+// findings from a that b no longer sees, keyed by method plus URL.
+func (s *Store) Diff(a, b int) (added, removed []Finding) {
+	inA := map[string]bool{}
+	inB := map[string]bool{}
+	for _, finding := range s.Findings {
+		key := finding.Method + " " + finding.URL
+		if finding.ScanID == a {
+			inA[key] = true
+		}
+		if finding.ScanID == b {
+			inB[key] = true
+		}
+	}
+	// Output order follows the store, so no re-sort is needed.
+	for _, finding := range s.Findings {
+		key := finding.Method + " " + finding.URL
+		if finding.ScanID == b && !inA[key] {
+			added = append(added, finding)
+		}
+		if finding.ScanID == a && !inB[key] {
+			removed = append(removed, finding)
+		}
+	}
+	return added, removed
+}
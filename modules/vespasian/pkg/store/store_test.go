@@ -0,0 +1,78 @@
+package store
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// TestRoundTrip saves a store and reads it back, covering both tables
+// and the full range of record value types.
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	s := &Store{path: path}
+	s.AppendScan(&types.ScanResult{
+		Targets:   []types.Target{{Host: "api.target.invalid"}},
+		StartedAt: types.NewTime(time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC)),
+		Endpoints: []types.Endpoint{
+			{URL: "http://api.target.invalid/users", Method: "GET", Source: "openapi",
+				Confidence: types.ConfidenceHigh, Metadata: map[string]string{"auth": "required"}},
+			{URL: "http://api.target.invalid/health", Method: "GET", Source: "crawler",
+				Confidence: types.ConfidenceMedium},
+		},
+	})
+	if err := s.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded, err := Open(path)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Scans, s.Scans) {
+		t.Errorf("scans = %+v, want %+v", loaded.Scans, s.Scans)
+	}
+	if !reflect.DeepEqual(loaded.Findings, s.Findings) {
+		t.Errorf("findings = %+v, want %+v", loaded.Findings, s.Findings)
+	}
+}
+
+// TestRoundTripLarge forces the b-tree past one leaf page and individual
+// records past the overflow threshold, the two paths a small store never
+// exercises.
+func TestRoundTripLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	s := &Store{path: path}
+	result := &types.ScanResult{
+		Targets:   []types.Target{{Host: "big.target.invalid"}},
+		StartedAt: types.NewTime(time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC)),
+	}
+	long := strings.Repeat("deadbeef", 2048) // 16 KiB, several overflow pages
+	for i := 0; i < 500; i++ {
+		result.Endpoints = append(result.Endpoints, types.Endpoint{
+			URL:      "http://big.target.invalid/api/" + strings.Repeat("x", i%50),
+			Method:   "GET",
+			Source:   "crawler",
+			Metadata: map[string]string{"blob": long},
+		})
+	}
+	s.AppendScan(result)
+	if err := s.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded, err := Open(path)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if len(loaded.Findings) != 500 {
+		t.Fatalf("got %d findings, want 500", len(loaded.Findings))
+	}
+	if !reflect.DeepEqual(loaded.Findings, s.Findings) {
+		t.Error("findings did not survive the round trip intact")
+	}
+}
@@ -0,0 +1,130 @@
+// Package targets imports scan targets in bulk from asset inventory
+// exports (Chariot JSON, CSV, plain newline-delimited host lists),
+// proxy exports, and nmap/masscan scan reports.
+package targets
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// ImportFile reads targets from path, detecting the format from the
+// content: JSON is treated as a masscan report or a Chariot asset
+// export, XML as an nmap report or a Burp sitemap or ZAP context
+// export, lines with commas as CSV (host[,port]), anything else as one
+// host[:port] per line.
+func ImportFile(path string) ([]types.Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+		if targets, ok := importMasscan(data); ok {
+			return targets, nil
+		}
+		return importChariot(data)
+	case strings.HasPrefix(trimmed, "<"):
+		if strings.Contains(trimmed, "<nmaprun") {
+			return importNmap(data)
+		}
+		return importProxyExport(data)
+	case strings.Contains(trimmed, ","):
+		return importCSV(trimmed)
+	default:
+		return importLines(trimmed)
+	}
+}
+
+// chariotAsset is the subset of a Chariot asset record vespasian uses.
+// Exports wrap assets either as a bare array or under a "data" key.
+type chariotAsset struct {
+	Key  string `json:"key"`
+	DNS  string `json:"dns"`
+	Name string `json:"name"`
+}
+
+func importChariot(data []byte) ([]types.Target, error) {
+	var assets []chariotAsset
+	if err := json.Unmarshal(data, &assets); err != nil {
+		var wrapper struct {
+			Data []chariotAsset `json:"data"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("targets: not a recognized Chariot export: %w", err)
+		}
+		assets = wrapper.Data
+	}
+	var targets []types.Target
+	seen := map[string]bool{}
+	for _, asset := range assets {
+		host := asset.DNS
+		if host == "" {
+			host = asset.Name
+		}
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		targets = append(targets, types.Target{Host: host})
+	}
+	return targets, nil
+}
+
+func importCSV(data string) ([]types.Target, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("targets: parsing CSV: %w", err)
+	}
+	var targets []types.Target
+	for i, record := range records {
+		host := strings.TrimSpace(record[0])
+		if host == "" || (i == 0 && strings.EqualFold(host, "host")) {
+			continue
+		}
+		target := types.Target{Host: host}
+		if len(record) > 1 {
+			portField := strings.TrimSpace(record[1])
+			if portField != "" && !strings.EqualFold(portField, "port") {
+				port, err := strconv.Atoi(portField)
+				if err != nil || port < 1 || port > 65535 {
+					return nil, fmt.Errorf("targets: invalid port %q on line %d", portField, i+1)
+				}
+				target.Port = port
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func importLines(data string) ([]types.Target, error) {
+	var targets []types.Target
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		host, portField, err := net.SplitHostPort(line)
+		if err != nil {
+			targets = append(targets, types.Target{Host: line})
+			continue
+		}
+		port, err := strconv.Atoi(portField)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("targets: invalid port in %q on line %d", line, i+1)
+		}
+		targets = append(targets, types.Target{Host: host, Port: port})
+	}
+	return targets, nil
+}
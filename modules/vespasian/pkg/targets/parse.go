@@ -0,0 +1,87 @@
+package targets
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Parse converts a "host", "host:port", "unix:///path", or full URL
+// string into a Target. Unix socket targets get a synthetic alias host
+// derived from the socket path, so HTTP probes address them like any
+// other target while the client dials the socket. URL targets keep
+// their explicit scheme and base path.
+func Parse(s string) (types.Target, error) {
+	if path, ok := strings.CutPrefix(s, "unix://"); ok {
+		if path == "" {
+			return types.Target{}, fmt.Errorf("empty socket path in target %q", s)
+		}
+		return types.Target{Host: socketAlias(path), Socket: path}, nil
+	}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return ParseURL(s)
+	}
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return types.Target{Host: s}, nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return types.Target{}, fmt.Errorf("invalid port in target %q", s)
+	}
+	return types.Target{Host: host, Port: port}, nil
+}
+
+// ParseURL converts a full URL ("https://host:9443/app") into a Target
+// carrying the explicit scheme, port, and base path. This is what makes
+// https on non-standard ports and sub-path deployments survive into
+// probing instead of being re-guessed from the port number.
+func ParseURL(s string) (types.Target, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return types.Target{}, fmt.Errorf("invalid target URL %q: %w", s, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return types.Target{}, fmt.Errorf("unsupported scheme %q in target %q", u.Scheme, s)
+	}
+	if u.Hostname() == "" {
+		return types.Target{}, fmt.Errorf("missing host in target %q", s)
+	}
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		port, err = strconv.Atoi(u.Port())
+		if err != nil || port < 1 || port > 65535 {
+			return types.Target{}, fmt.Errorf("invalid port in target %q", s)
+		}
+	}
+	return types.Target{
+		Host:     u.Hostname(),
+		Port:     port,
+		Scheme:   u.Scheme,
+		BasePath: strings.TrimSuffix(u.Path, "/"),
+	}, nil
+}
+
+// socketAlias turns a socket path into a hostname-safe alias:
+// /var/run/docker.sock becomes var-run-docker-sock.
+func socketAlias(path string) string {
+	alias := strings.Trim(path, "/")
+	alias = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, alias)
+	return alias
+}
@@ -0,0 +1,97 @@
+package targets
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// importProxyExport reads targets out of an interactive proxy's export:
+// a Burp XML sitemap (<items> with one <url> per request) or a ZAP
+// context file (<context> with include regexes). Each distinct host:port
+// becomes one target, so a sitemap with thousands of requests seeds a
+// scan without duplicating work.
+func importProxyExport(data []byte) ([]types.Target, error) {
+	var burp burpSitemap
+	if err := xml.Unmarshal(data, &burp); err == nil && len(burp.Items) > 0 {
+		return targetsFromURLs(burpURLs(burp))
+	}
+	var zap zapContext
+	if err := xml.Unmarshal(data, &zap); err == nil && len(zap.IncludeRegexes) > 0 {
+		return targetsFromURLs(zapURLs(zap))
+	}
+	return nil, fmt.Errorf("targets: not a recognized Burp sitemap or ZAP context export")
+}
+
+// burpSitemap is the subset of a Burp "Save selected items" XML export
+// the importer reads.
+type burpSitemap struct {
+	XMLName xml.Name `xml:"items"`
+	Items   []struct {
+		URL string `xml:"url"`
+	} `xml:"item"`
+}
+
+func burpURLs(sitemap burpSitemap) []string {
+	urls := make([]string, 0, len(sitemap.Items))
+	for _, item := range sitemap.Items {
+		urls = append(urls, strings.TrimSpace(item.URL))
+	}
+	return urls
+}
+
+// zapContext is the subset of a ZAP context export the importer reads.
+// Include regexes look like "https://example.com.*" with regex escapes.
+type zapContext struct {
+	XMLName        xml.Name `xml:"configuration"`
+	IncludeRegexes []string `xml:"context>incregexes"`
+}
+
+func zapURLs(context zapContext) []string {
+	urls := make([]string, 0, len(context.IncludeRegexes))
+	for _, regex := range context.IncludeRegexes {
+		// Strip the trailing wildcard and unescape the common regex
+		// escapes ZAP writes, leaving a parseable URL prefix.
+		cleaned := strings.TrimSuffix(strings.TrimSpace(regex), ".*")
+		cleaned = strings.ReplaceAll(cleaned, `\.`, ".")
+		cleaned = strings.ReplaceAll(cleaned, `\/`, "/")
+		urls = append(urls, cleaned)
+	}
+	return urls
+}
+
+// targetsFromURLs reduces URLs to their distinct host:port targets.
+func targetsFromURLs(urls []string) ([]types.Target, error) {
+	var targets []types.Target
+	seen := map[string]bool{}
+	for _, raw := range urls {
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+		target := types.Target{Host: parsed.Hostname()}
+		if portField := parsed.Port(); portField != "" {
+			port, err := strconv.Atoi(portField)
+			if err != nil || port < 1 || port > 65535 {
+				continue
+			}
+			target.Port = port
+		} else if parsed.Scheme == "https" {
+			target.Port = 443
+		}
+		key := target.Address()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("targets: no usable URLs in proxy export")
+	}
+	return targets, nil
+}
@@ -0,0 +1,148 @@
+package targets
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// importNmap reads an nmap XML report (-oX) and emits one target per
+// open port. Nmap's service detection is carried over as a service
+// hint, so a port nmap identified as https is probed over TLS and a
+// port it identified as ssh is skipped by the HTTP probes without
+// vespasian re-detecting either.
+func importNmap(data []byte) ([]types.Target, error) {
+	var run nmapRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("targets: parsing nmap XML: %w", err)
+	}
+	var targets []types.Target
+	for _, host := range run.Hosts {
+		name := nmapHostName(host)
+		if name == "" {
+			continue
+		}
+		for _, port := range host.Ports {
+			if port.State.State != "open" {
+				continue
+			}
+			targets = append(targets, types.Target{
+				Host:    name,
+				Port:    port.PortID,
+				Service: serviceHint(port.Service.Name, port.Service.Tunnel),
+			})
+		}
+	}
+	return targets, nil
+}
+
+// nmapRun is the subset of an nmap XML report the importer reads.
+type nmapRun struct {
+	XMLName xml.Name   `xml:"nmaprun"`
+	Hosts   []nmapHost `xml:"host"`
+}
+
+type nmapHost struct {
+	Addresses []struct {
+		Addr string `xml:"addr,attr"`
+		Type string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []struct {
+		PortID int `xml:"portid,attr"`
+		State  struct {
+			State string `xml:"state,attr"`
+		} `xml:"state"`
+		Service struct {
+			Name   string `xml:"name,attr"`
+			Tunnel string `xml:"tunnel,attr"`
+		} `xml:"service"`
+	} `xml:"ports>port"`
+}
+
+// nmapHostName prefers the DNS name nmap resolved over the raw address,
+// since virtual-hosted services only answer to the name.
+func nmapHostName(host nmapHost) string {
+	for _, hostname := range host.Hostnames {
+		if hostname.Name != "" {
+			return hostname.Name
+		}
+	}
+	for _, address := range host.Addresses {
+		if address.Type == "ipv4" || address.Type == "ipv6" {
+			return address.Addr
+		}
+	}
+	if len(host.Addresses) > 0 {
+		return host.Addresses[0].Addr
+	}
+	return ""
+}
+
+// masscanRecord is one entry of a masscan JSON report (-oJ). The
+// service block only appears when masscan ran with banner grabbing.
+type masscanRecord struct {
+	IP    string `json:"ip"`
+	Ports []struct {
+		Port    int    `json:"port"`
+		Status  string `json:"status"`
+		Service struct {
+			Name string `json:"name"`
+		} `json:"service"`
+	} `json:"ports"`
+}
+
+// importMasscan reads a masscan JSON report. The ok result reports
+// whether the data looked like masscan output at all, so ImportFile can
+// fall back to the Chariot importer for other JSON arrays.
+func importMasscan(data []byte) ([]types.Target, bool) {
+	var records []masscanRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, false
+	}
+	shaped := false
+	var targets []types.Target
+	for _, record := range records {
+		if record.IP == "" || len(record.Ports) == 0 {
+			continue
+		}
+		shaped = true
+		for _, port := range record.Ports {
+			if port.Status != "" && port.Status != "open" {
+				continue
+			}
+			targets = append(targets, types.Target{
+				Host:    record.IP,
+				Port:    port.Port,
+				Service: serviceHint(port.Service.Name, ""),
+			})
+		}
+	}
+	return targets, shaped
+}
+
+// serviceHint converts a scanner's service identification into a
+// pre-seeded detection verdict. An empty or unrecognized name returns
+// nil so the built-in service detection still gets its say.
+func serviceHint(name, tunnel string) *types.Service {
+	name = strings.ToLower(name)
+	switch {
+	case name == "":
+		return nil
+	case strings.Contains(name, "http"):
+		return &types.Service{
+			HTTP: true,
+			TLS:  tunnel == "ssl" || strings.Contains(name, "https"),
+		}
+	default:
+		// A confident non-HTTP identification (ssh, smtp, mysql, ...)
+		// is worth recording: it routes the port out of the HTTP probe
+		// set instead of burning requests on it.
+		return &types.Service{TLS: tunnel == "ssl"}
+	}
+}
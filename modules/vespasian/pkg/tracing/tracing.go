@@ -0,0 +1,238 @@
+// Package tracing records spans for scans and individual probe runs and
+// exports them to an OpenTelemetry collector over OTLP/HTTP in the
+// protobuf-JSON encoding. Like pkg/metrics this is the stdlib-only
+// subset of a telemetry SDK: vespasian vendors no dependencies, and the
+// OTLP/JSON trace payload is a stable documented wire format every
+// collector accepts on its /v1/traces endpoint. Spans buffer in memory
+// and ship in one batch at Flush; until Enable configures an endpoint,
+// StartSpan returns nil and every span operation is a no-op.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	mu       sync.Mutex
+	endpoint string
+	finished []*Span
+)
+
+// Enable turns span collection on and sets the OTLP/HTTP collector base
+// URL (e.g. "http://collector:4318"); spans post to its /v1/traces
+// path. Call it once at startup.
+func Enable(collectorURL string) {
+	mu.Lock()
+	endpoint = collectorURL
+	mu.Unlock()
+}
+
+func enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return endpoint != ""
+}
+
+// Span is one timed operation within a trace. A nil *Span is valid and
+// inert, so callers never guard on whether tracing is enabled.
+type Span struct {
+	traceID string
+	spanID  string
+	parent  string
+	name    string
+	start   time.Time
+	end     time.Time
+	attrs   []attribute
+	errMsg  string
+	failed  bool
+}
+
+type attribute struct {
+	key   string
+	value string
+}
+
+// StartSpan opens a span under parent; a nil parent starts a new trace.
+func StartSpan(parent *Span, name string) *Span {
+	if !enabled() {
+		return nil
+	}
+	s := &Span{name: name, start: time.Now(), spanID: randomHex(8)}
+	if parent != nil {
+		s.traceID = parent.traceID
+		s.parent = parent.spanID
+	} else {
+		s.traceID = randomHex(16)
+	}
+	return s
+}
+
+// SetAttribute attaches a key/value pair to the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.attrs = append(s.attrs, attribute{key: key, value: value})
+}
+
+// SetError marks the span failed with the given message.
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+	s.failed = true
+	s.errMsg = message
+}
+
+// End closes the span and queues it for export.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.end = time.Now()
+	mu.Lock()
+	finished = append(finished, s)
+	mu.Unlock()
+}
+
+// Flush posts every finished span to the collector in one OTLP/HTTP
+// request. It is a no-op when tracing is disabled or nothing finished.
+func Flush(ctx context.Context) error {
+	mu.Lock()
+	spans := finished
+	finished = nil
+	url := endpoint
+	mu.Unlock()
+	if url == "" || len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(exportRequest(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/v1/traces", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return fmt.Errorf("tracing: exporting to %s: %w", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("tracing: collector %s answered %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// The OTLP/JSON shapes below follow the proto3 JSON mapping of
+// ExportTraceServiceRequest; unix-nanosecond timestamps render as
+// strings per that mapping.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	statusOK     = 1
+	statusError  = 2
+	kindInternal = 1
+)
+
+func exportRequest(spans []*Span) otlpRequest {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           s.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parent,
+			Name:              s.name,
+			Kind:              kindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Status:            otlpStatus{Code: statusOK},
+		}
+		if s.failed {
+			span.Status = otlpStatus{Code: statusError, Message: s.errMsg}
+		}
+		for _, a := range s.attrs {
+			span.Attributes = append(span.Attributes, otlpAttribute{
+				Key:   a.key,
+				Value: otlpValue{StringValue: a.value},
+			})
+		}
+		encoded = append(encoded, span)
+	}
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: []otlpAttribute{{
+				Key:   "service.name",
+				Value: otlpValue{StringValue: "vespasian"},
+			}}},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "vespasian"},
+				Spans: encoded,
+			}},
+		}},
+	}
+}
+
+func randomHex(bytes int) string {
+	b := make([]byte, bytes)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestExport asserts spans reach the collector on the OTLP/HTTP traces
+// path with their trace lineage, attributes, and error status intact.
+func TestExport(t *testing.T) {
+	received := make(chan otlpRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("export arrived as %s %s (%s)", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var request otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding export: %v", err)
+		}
+		received <- request
+	}))
+	defer collector.Close()
+	Enable(collector.URL)
+	defer Enable("")
+
+	scan := StartSpan(nil, "scan")
+	probe := StartSpan(scan, "probe graphql")
+	probe.SetAttribute("target", "api.target.invalid")
+	probe.SetError("connection refused")
+	probe.End()
+	scan.End()
+	if err := Flush(context.Background()); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	request := <-received
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	child, root := spans[0], spans[1]
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID {
+		t.Errorf("probe span not parented under the scan span: %+v / %+v", child, root)
+	}
+	if child.Status.Code != statusError || child.Status.Message != "connection refused" {
+		t.Errorf("probe status = %+v, want the recorded error", child.Status)
+	}
+	if root.StartTimeUnixNano == "" || root.EndTimeUnixNano == "" {
+		t.Errorf("scan span missing timestamps: %+v", root)
+	}
+	resource := request.ResourceSpans[0].Resource.Attributes
+	if len(resource) == 0 || resource[0].Key != "service.name" || resource[0].Value.StringValue != "vespasian" {
+		t.Errorf("resource attributes = %+v, want service.name vespasian", resource)
+	}
+}
+
+// TestDisabled asserts span operations are inert without a configured
+// endpoint, which is how every scan runs by default.
+func TestDisabled(t *testing.T) {
+	if span := StartSpan(nil, "scan"); span != nil {
+		t.Errorf("StartSpan returned %+v while disabled", span)
+	}
+	var span *Span
+	span.SetAttribute("probe", "graphql") // must not panic
+	span.SetError("boom")
+	span.End()
+	if err := Flush(context.Background()); err != nil {
+		t.Errorf("flush while disabled: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Time is a timestamp that renders consistently across every writer and
+// the scan manifest, honoring the process-wide timezone and format
+// configured through SetTimeFormat. Mixed local-time output across files
+// complicates cross-team correlation of long scans; this keeps every
+// timestamp in one zone and one format.
+type Time struct {
+	time.Time
+}
+
+// NewTime wraps t.
+func NewTime(t time.Time) Time { return Time{t} }
+
+var (
+	formatMu sync.RWMutex
+	location = time.UTC
+	asEpoch  bool
+)
+
+// SetTimeFormat configures how Time values marshal: in the given location
+// (nil keeps UTC) and, when epoch is true, as Unix seconds instead of
+// RFC 3339. Call it once at startup, before output is produced.
+func SetTimeFormat(loc *time.Location, epoch bool) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	if loc != nil {
+		location = loc
+	}
+	asEpoch = epoch
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Time) MarshalJSON() ([]byte, error) {
+	formatMu.RLock()
+	loc, epoch := location, asEpoch
+	formatMu.RUnlock()
+	if epoch {
+		return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+	}
+	return []byte(strconv.Quote(t.In(loc).Format(time.RFC3339))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both RFC 3339
+// strings and Unix-second numbers.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] != '"' {
+		seconds, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return err
+		}
+		t.Time = time.Unix(seconds, 0).UTC()
+		return nil
+	}
+	raw, err := strconv.Unquote(string(data))
+	if err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
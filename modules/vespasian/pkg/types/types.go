@@ -0,0 +1,125 @@
+// Package types defines the core data model shared across vespasian:
+// scan targets, discovered endpoints, and scan results.
+package types
+
+import (
+	"net"
+	"strconv"
+)
+
+// Target is a single host under test.
+type Target struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// Scheme, when set, is the URL scheme probes use ("http" or
+	// "https"). Empty falls back to guessing from the port, which gets
+	// https on non-standard ports wrong; URL targets set it explicitly.
+	Scheme string `json:"scheme,omitempty"`
+	// BasePath prefixes every probed path when the service is deployed
+	// under a sub-path ("/app"); stored without a trailing slash.
+	BasePath string `json:"base_path,omitempty"`
+	// Socket, when set, is the Unix domain socket path serving the
+	// target. Host then carries a synthetic alias used in URLs and
+	// results; connections go through the socket instead of TCP.
+	Socket string `json:"socket,omitempty"`
+	// Service is the pre-flight service detection verdict for this
+	// host and port; nil means detection did not run.
+	Service *Service `json:"service,omitempty"`
+}
+
+// Service is what pre-flight detection learned by touching the port
+// once: whether anything answered HTTP, whether the port speaks TLS,
+// and the ALPN protocol a TLS handshake negotiated.
+type Service struct {
+	HTTP bool   `json:"http"`
+	TLS  bool   `json:"tls"`
+	ALPN string `json:"alpn,omitempty"`
+}
+
+// Address returns the host:port form of the target.
+func (t Target) Address() string {
+	if t.Port == 0 {
+		return t.Host
+	}
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// BaseURL returns the root URL probes prepend paths to:
+// scheme://host:port plus any base path. Without an explicit Scheme,
+// the service detection verdict decides, and failing that https is
+// assumed on the conventional TLS ports and http elsewhere.
+func (t Target) BaseURL() string {
+	scheme := t.Scheme
+	if scheme == "" {
+		switch {
+		case t.Service != nil && t.Service.TLS:
+			scheme = "https"
+		case t.Service != nil:
+			scheme = "http"
+		case t.Port == 443 || t.Port == 8443:
+			scheme = "https"
+		default:
+			scheme = "http"
+		}
+	}
+	return scheme + "://" + t.Address() + t.BasePath
+}
+
+// Protocol constants for discovered endpoints.
+const (
+	ProtocolHTTP      = "http"
+	ProtocolGraphQL   = "graphql"
+	ProtocolGRPC      = "grpc"
+	ProtocolWebSocket = "websocket"
+	ProtocolSOAP      = "soap"
+	ProtocolKafka     = "kafka"
+	ProtocolAMQP      = "amqp"
+	ProtocolMQTT      = "mqtt"
+)
+
+// Confidence levels for discovered endpoints. Probes score how certain
+// they are that an endpoint is real: parsed from an authoritative spec is
+// High, observed directly is Medium, inferred from naming conventions or
+// partial signals is Low.
+const (
+	ConfidenceHigh   = 1.0
+	ConfidenceMedium = 0.5
+	ConfidenceLow    = 0.25
+)
+
+// Endpoint is a single discovered API endpoint.
+type Endpoint struct {
+	URL        string            `json:"url"`
+	Method     string            `json:"method,omitempty"`
+	Protocol   string            `json:"protocol"`
+	Source     string            `json:"source"`
+	Confidence float64           `json:"confidence"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// TargetHealth summarizes a target's reachability during the scan:
+// incidental infrastructure data the probes generate anyway, reported as
+// a per-target SLA-style block.
+type TargetHealth struct {
+	Target string `json:"target"`
+	// Reachable reports whether the health-check request completed.
+	Reachable bool `json:"reachable"`
+	// Latency is the round-trip time of the health-check request.
+	Latency string `json:"latency,omitempty"`
+	// TLSHandshake is the TLS handshake duration, for TLS targets.
+	TLSHandshake string `json:"tls_handshake,omitempty"`
+	// Probes and Errors count probe executions against the target and how
+	// many of them failed; ErrorRate is Errors/Probes.
+	Probes    int     `json:"probes"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// ScanResult aggregates everything discovered during a single scan run.
+type ScanResult struct {
+	Targets    []Target       `json:"targets"`
+	Endpoints  []Endpoint     `json:"endpoints"`
+	Health     []TargetHealth `json:"health,omitempty"`
+	StartedAt  Time           `json:"started_at"`
+	FinishedAt Time           `json:"finished_at"`
+}
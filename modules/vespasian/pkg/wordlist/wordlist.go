@@ -0,0 +1,75 @@
+// Package wordlist maintains a learned wordlist of endpoint paths
+// aggregated across stored scans. Repeated engagements against similar
+// stacks keep rediscovering the same paths; ranking them by how often
+// they appeared makes later scans find them first.
+package wordlist
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/praetorian-inc/vespasian/pkg/types"
+)
+
+// Update folds the paths of the given endpoints into the wordlist file,
+// creating it on first use. Counts persist as a JSON path→frequency map.
+func Update(path string, endpoints []types.Endpoint) error {
+	counts, err := load(path)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || u.Path == "" || u.Path == "/" {
+			continue
+		}
+		counts[u.Path]++
+	}
+	data, err := json.MarshalIndent(counts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load returns up to limit learned paths ranked by frequency, most
+// frequent first; ties break alphabetically so the ordering is stable. A
+// missing file yields an empty list, not an error.
+func Load(path string, limit int) ([]string, error) {
+	counts, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(counts))
+	for p := range counts {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if counts[paths[i]] != counts[paths[j]] {
+			return counts[paths[i]] > counts[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	if limit > 0 && len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths, nil
+}
+
+func load(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
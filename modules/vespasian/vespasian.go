@@ -0,0 +1,121 @@
+// Package vespasian exposes endpoint discovery as a library. It wires the
+// probe registry, HTTP client, and scan pipeline together so embedding
+// consumers do not have to reproduce the CLI's integration code.
+package vespasian
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/praetorian-inc/vespasian/pkg/client"
+	"github.com/praetorian-inc/vespasian/pkg/probe"
+	"github.com/praetorian-inc/vespasian/pkg/scan"
+	"github.com/praetorian-inc/vespasian/pkg/targets"
+	"github.com/praetorian-inc/vespasian/pkg/types"
+
+	_ "github.com/praetorian-inc/vespasian/pkg/probes"
+)
+
+// Finding is a discovered endpoint.
+type Finding = types.Endpoint
+
+// Option configures a Scanner.
+type Option func(*settings)
+
+type settings struct {
+	clientOpts client.Options
+	scanOpts   scan.Options
+	probeNames []string
+}
+
+// WithConcurrency sets how many probe executions run in parallel.
+func WithConcurrency(n int) Option {
+	return func(s *settings) { s.scanOpts.Concurrency = n }
+}
+
+// WithTimeout bounds each HTTP request.
+func WithTimeout(d time.Duration) Option {
+	return func(s *settings) { s.clientOpts.Timeout = d }
+}
+
+// WithInsecureTLS disables TLS certificate verification.
+func WithInsecureTLS() Option {
+	return func(s *settings) { s.clientOpts.InsecureSkipVerify = true }
+}
+
+// WithMinConfidence drops findings scored below the threshold (0..1).
+func WithMinConfidence(min float64) Option {
+	return func(s *settings) { s.scanOpts.MinConfidence = min }
+}
+
+// WithProbes restricts the scan to the named probes instead of every
+// registered one.
+func WithProbes(names ...string) Option {
+	return func(s *settings) { s.probeNames = names }
+}
+
+// WithHTTPVersion pins the HTTP protocol version ("auto", "http/1.1", "h2").
+func WithHTTPVersion(version string) Option {
+	return func(s *settings) { s.clientOpts.HTTPVersion = version }
+}
+
+// Scanner runs endpoint discovery for library consumers.
+type Scanner struct {
+	scanner *scan.Scanner
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewScanner builds a Scanner from options, with the same defaults the CLI
+// applies.
+func NewScanner(opts ...Option) (*Scanner, error) {
+	var cfg settings
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	for _, name := range cfg.probeNames {
+		p, ok := probe.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("vespasian: unknown probe %q", name)
+		}
+		cfg.scanOpts.Probes = append(cfg.scanOpts.Probes, p)
+	}
+	cfg.scanOpts.Client = client.New(cfg.clientOpts)
+	return &Scanner{scanner: scan.New(cfg.scanOpts)}, nil
+}
+
+// Scan discovers endpoints on targets ("host" or "host:port" strings) and
+// streams findings on the returned channel as probes report them; the
+// channel closes when the scan finishes. Check Err after the channel
+// closes for scan failures.
+func (s *Scanner) Scan(ctx context.Context, targetStrs []string) (<-chan Finding, error) {
+	parsed := make([]types.Target, 0, len(targetStrs))
+	for _, t := range targetStrs {
+		target, err := targets.Parse(t)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, target)
+	}
+
+	findings := make(chan Finding)
+	go func() {
+		if _, err := s.scanner.ScanStream(ctx, parsed, findings); err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+		}
+	}()
+	return findings, nil
+}
+
+// Err reports the error, if any, from the most recent Scan. It is only
+// meaningful after the findings channel has closed.
+func (s *Scanner) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}